@@ -49,7 +49,7 @@ func formatBecome(name, lens, env string) string {
 }
 
 func applyBecome(s *State, name, lens, env string) {
-	s.Identity = &Identity{Name: name, Lens: lens, Env: env}
+	s.PushIdentity(Identity{Name: name, Lens: lens, Env: env})
 	s.AddHistory(HistoryEntry{
 		Action: "become",
 		Params: map[string]string{"name": name, "lens": lens, "env": env},
@@ -61,9 +61,12 @@ The modes do not alternate. They overlap on the same observation. Do not let one
 }
 
 func applyChord(s *State, modes []string, target string) {
+	CloseConstraintsOn(s, BoundaryChord)
+	id := OpenConstraint(s, "chord", fmt.Sprintf("[%s] on %s", strings.Join(modes, " + "), target))
 	s.AddHistory(HistoryEntry{
 		Action: "chord",
 		Params: map[string]string{
+			"id":     id,
 			"modes":  strings.Join(modes, "; "),
 			"target": target,
 		},
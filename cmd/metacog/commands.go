@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -11,20 +12,44 @@ func FormatStatus(s *State) string {
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("Session: %s\n", s.SessionID))
 	if s.Session != "" {
-		b.WriteString(fmt.Sprintf("Active session: %s\n", s.Session))
+		b.WriteString(fmt.Sprintf("Active session: %s", s.Session))
+		if len(s.SessionParents) > 0 {
+			b.WriteString(fmt.Sprintf(" (within %s)", strings.Join(s.SessionParents, " > ")))
+		}
+		b.WriteString("\n")
 	}
 	b.WriteString("\n")
 
-	if s.Identity != nil {
-		b.WriteString(fmt.Sprintf("Identity: %s\n  Lens: %s\n  Environment: %s\n\n", s.Identity.Name, s.Identity.Lens, s.Identity.Env))
-	} else {
+	identities := s.Identities()
+	switch len(identities) {
+	case 0:
 		b.WriteString("Identity: (none)\n\n")
+	case 1:
+		id := identities[0]
+		b.WriteString(fmt.Sprintf("Identity: %s\n  Lens: %s\n  Environment: %s\n\n", id.Name, id.Lens, id.Env))
+	default:
+		b.WriteString(fmt.Sprintf("Identity stack (%d layers, top first):\n", len(identities)))
+		for i := len(identities) - 1; i >= 0; i-- {
+			id := identities[i]
+			b.WriteString(fmt.Sprintf("  %d. %s\n     Lens: %s\n     Environment: %s\n", i+1, id.Name, id.Lens, id.Env))
+		}
+		b.WriteString("\n")
 	}
 
-	if s.Substrate != nil {
-		b.WriteString(fmt.Sprintf("Substrate: %s\n  Method: %s\n  Qualia: %s\n\n", s.Substrate.Substance, s.Substrate.Method, s.Substrate.Qualia))
-	} else {
+	substrates := s.Substrates()
+	switch len(substrates) {
+	case 0:
 		b.WriteString("Substrate: (none)\n\n")
+	case 1:
+		sub := substrates[0]
+		b.WriteString(fmt.Sprintf("Substrate: %s\n  Method: %s\n  Qualia: %s\n\n", sub.Substance, sub.Method, sub.Qualia))
+	default:
+		b.WriteString(fmt.Sprintf("Substrate stack (%d layers, top first):\n", len(substrates)))
+		for i := len(substrates) - 1; i >= 0; i-- {
+			sub := substrates[i]
+			b.WriteString(fmt.Sprintf("  %d. %s\n     Method: %s\n     Qualia: %s\n", i+1, sub.Substance, sub.Method, sub.Qualia))
+		}
+		b.WriteString("\n")
 	}
 
 	if s.Stratagem != nil {
@@ -33,6 +58,14 @@ func FormatStatus(s *State) string {
 		b.WriteString("Stratagem: (none)\n")
 	}
 
+	if OpenThreadCount(s) > 0 {
+		b.WriteString("\n")
+		b.WriteString(FormatThreads(s))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(FormatConstraints(s))
+
 	return b.String()
 }
 
@@ -68,26 +101,36 @@ var statusCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		fmt.Println(FormatOutput(jsonOutput, FormatStatus(s), nil))
+		output := FormatStatus(s)
+		var idleTimeout time.Duration
+		if cfg, err := sm.LoadConfig(); err == nil {
+			idleTimeout, _ = cfg.Session.IdleTimeoutDuration()
+		}
+		if warning := AbandonedSessionWarning(s, time.Now().UTC(), idleTimeout); warning != "" {
+			output += "\n" + warning
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
 		return nil
 	},
 }
 
 var resetCmd = &cobra.Command{
 	Use:   "reset",
-	Short: "Clear identity, substrate, and stratagem (preserves session and history)",
+	Short: "Clear identity, substrate, stratagem, and active constraints (preserves session and history)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sm := DefaultStateManager()
 		err := sm.SaveWithLock(func(s *State) error {
-			s.Identity = nil
-			s.Substrate = nil
+			s.ClearLayers()
 			s.Stratagem = nil
+			for len(s.Constraints) > 0 {
+				CloseConstraint(s, s.Constraints[0].ID, "reset", "")
+			}
 			return nil
 		})
 		if err != nil {
 			return err
 		}
-		fmt.Println(FormatOutput(jsonOutput, "State reset. Identity, substrate, and stratagem cleared.", nil))
+		fmt.Println(FormatOutput(jsonOutput, "State reset. Identity, substrate, stratagem, and constraints cleared.", nil))
 		return nil
 	},
 }
@@ -95,6 +138,8 @@ var resetCmd = &cobra.Command{
 var historyFull bool
 var historySession string
 
+// mergeArchivedHistory returns s with the history archive prepended and
+// archived outcomes' amendments applied.
 func mergeArchivedHistory(sm *StateManager, s *State) (*State, error) {
 	archived, err := sm.LoadHistoryArchive()
 	if err != nil {
@@ -105,6 +150,7 @@ func mergeArchivedHistory(sm *StateManager, s *State) (*State, error) {
 	}
 	merged := *s
 	merged.History = append(archived, s.History...)
+	applyOutcomeAmendments(merged.History)
 	return &merged, nil
 }
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -37,7 +38,101 @@ var commitmentCmd = &cobra.Command{
 	},
 }
 
+var (
+	commFalsifyReason string
+	commResolveHeld   bool
+	commResolveFalse  bool
+	commEvidence      string
+	commListOpen      bool
+	commListClosed    bool
+)
+
+var commitmentFalsifyCmd = &cobra.Command{
+	Use:   "falsify [id]",
+	Short: "Resolve a commitment as falsified (shorthand for resolve --falsified)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if commFalsifyReason == "" {
+			return fmt.Errorf("--reason is required: name what triggered the falsifier")
+		}
+		return runResolveCommitment(args, VerdictFalsified, commFalsifyReason)
+	},
+}
+
+var commitmentResolveCmd = &cobra.Command{
+	Use:   "resolve [id]",
+	Short: "Record whether a commitment held or its falsifier fired",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if commResolveHeld == commResolveFalse {
+			return fmt.Errorf("exactly one of --held or --falsified is required")
+		}
+		if commEvidence == "" {
+			return fmt.Errorf("--evidence is required: what did you observe?")
+		}
+		verdict := VerdictHeld
+		if commResolveFalse {
+			verdict = VerdictFalsified
+		}
+		return runResolveCommitment(args, verdict, commEvidence)
+	},
+}
+
+func runResolveCommitment(args []string, verdict, evidence string) error {
+	ref := ""
+	if len(args) == 1 {
+		ref = args[0]
+	}
+	sm := DefaultStateManager()
+	var output string
+	err := sm.SaveWithLock(func(s *State) error {
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		c, err := ResolveCommitment(s, merged.History, ref, verdict, evidence)
+		if err != nil {
+			return err
+		}
+		output = fmt.Sprintf("Commitment %s %s: %s\nEvidence: %s", c.ID, verdict, c.Binding, evidence)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(FormatOutput(jsonOutput, output, nil))
+	return nil
+}
+
+var commitmentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List commitments and whether they held, were falsified, or are unresolved",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		s, err := sm.Load()
+		if err != nil {
+			return err
+		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		records := CollectCommitments(s, merged.History)
+		fmt.Println(FormatOutput(jsonOutput, FormatCommitmentList(records, commListOpen, commListClosed), nil))
+		return nil
+	},
+}
+
 func init() {
+	commitmentFalsifyCmd.Flags().StringVar(&commFalsifyReason, "reason", "", "What triggered the falsifier (required)")
+	commitmentResolveCmd.Flags().BoolVar(&commResolveHeld, "held", false, "The binding held; the falsifier did not fire")
+	commitmentResolveCmd.Flags().BoolVar(&commResolveFalse, "falsified", false, "The falsifier fired")
+	commitmentResolveCmd.Flags().StringVar(&commEvidence, "evidence", "", "What you observed (required)")
+	commitmentListCmd.Flags().BoolVar(&commListOpen, "open", false, "Only unresolved commitments")
+	commitmentListCmd.Flags().BoolVar(&commListClosed, "closed", false, "Only resolved commitments")
+	commitmentCmd.AddCommand(commitmentFalsifyCmd)
+	commitmentCmd.AddCommand(commitmentResolveCmd)
+	commitmentCmd.AddCommand(commitmentListCmd)
 	commitmentCmd.Flags().StringVar(&commBinding, "binding", "", "The stance/prediction/position being committed to")
 	commitmentCmd.Flags().StringVar(&commStakes, "stakes", "", "What is on the line if the binding turns out wrong")
 	commitmentCmd.Flags().StringVar(&commFalsifier, "falsifier", "", "Specific observation that would falsify the binding")
@@ -58,16 +153,174 @@ BINDING: %s
 STAKES: %s
 FALSIFIER: %s
 
-Reasoning that follows is now constrained by this binding. Motivated reasoning becomes visible against the falsifier. To release the commitment, name the falsifier as triggered or invoke ritual to seal a different ground.`, binding, stakes, falsifier)
+Reasoning that follows is now constrained by this binding. Motivated reasoning becomes visible against the falsifier. To release the commitment, name the falsifier as triggered ('metacog commitment falsify --reason ...') or invoke ritual to seal a different ground.`, binding, stakes, falsifier)
+}
+
+const (
+	VerdictHeld      = "held"
+	VerdictFalsified = "falsified"
+)
+
+// CommitmentRecord joins a commitment with its resolution, if any.
+type CommitmentRecord struct {
+	ID         string
+	Binding    string
+	Stakes     string
+	Falsifier  string
+	Stratagem  string
+	MadeAt     string
+	Verdict    string
+	Evidence   string
+	ResolvedAt string
+	Active     bool
+}
+
+// CollectCommitments pairs commitment entries in history with their resolutions, oldest first.
+func CollectCommitments(s *State, history []HistoryEntry) []CommitmentRecord {
+	var records []CommitmentRecord
+	byID := map[string]int{}
+	for _, h := range history {
+		switch h.Action {
+		case "commitment":
+			r := CommitmentRecord{
+				ID:        h.Params["id"],
+				Binding:   h.Params["binding"],
+				Stakes:    h.Params["stakes"],
+				Falsifier: h.Params["falsifier"],
+				Stratagem: h.Params["stratagem"],
+				MadeAt:    h.Timestamp,
+			}
+			if r.ID != "" {
+				_, r.Active = FindConstraint(s, r.ID)
+				byID[r.ID] = len(records)
+			}
+			records = append(records, r)
+		case "resolution":
+			if i, ok := byID[h.Params["id"]]; ok {
+				records[i].Verdict = h.Params["verdict"]
+				records[i].Evidence = h.Params["evidence"]
+				records[i].ResolvedAt = h.Timestamp
+			}
+		}
+	}
+	return records
+}
+
+// UnresolvedCommitments counts commitments in history with no held/falsified
+// verdict. Commitments recorded before they had IDs can't be resolved, so they
+// aren't counted.
+func UnresolvedCommitments(s *State, history []HistoryEntry) int {
+	n := 0
+	for _, r := range CollectCommitments(s, history) {
+		if r.ID != "" && r.Verdict == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// CommitmentReminder nudges toward checking falsifiers; empty when nothing is unresolved.
+func CommitmentReminder(s *State, history []HistoryEntry) string {
+	n := UnresolvedCommitments(s, history)
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nReminder: %d unresolved commitments. Did the falsifier fire? 'metacog commitment list --open', then 'metacog commitment resolve <id> --held|--falsified --evidence ...'", n)
+}
+
+// ResolveCommitment records whether a commitment held. With an empty ref the
+// most recent unresolved commitment is resolved. An active binding is closed.
+// history is the full history ending with s.History.
+func ResolveCommitment(s *State, history []HistoryEntry, ref, verdict, evidence string) (CommitmentRecord, error) {
+	if verdict != VerdictHeld && verdict != VerdictFalsified {
+		return CommitmentRecord{}, fmt.Errorf("verdict must be %q or %q, got %q", VerdictHeld, VerdictFalsified, verdict)
+	}
+	records := CollectCommitments(s, history)
+	idx := -1
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].ID == "" {
+			continue
+		}
+		if (ref == "" && records[i].Verdict == "") || records[i].ID == ref {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		if ref == "" {
+			return CommitmentRecord{}, fmt.Errorf("no unresolved commitments")
+		}
+		return CommitmentRecord{}, fmt.Errorf("no commitment %q. Run 'metacog commitment list' to see IDs", ref)
+	}
+	r := records[idx]
+	if r.Verdict != "" {
+		return CommitmentRecord{}, fmt.Errorf("commitment %s already resolved as %s", r.ID, r.Verdict)
+	}
+
+	if r.Active {
+		CloseConstraint(s, r.ID, verdict, evidence)
+		r.Active = false
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "resolution",
+		Params: map[string]string{"id": r.ID, "verdict": verdict, "evidence": evidence},
+	})
+	r.Verdict = verdict
+	r.Evidence = evidence
+	return r, nil
+}
+
+func FormatCommitmentList(records []CommitmentRecord, openOnly, closedOnly bool) string {
+	var b strings.Builder
+	shown := 0
+	for _, r := range records {
+		resolved := r.Verdict != ""
+		// Commitments from before IDs can't be resolved; they aren't open
+		untracked := r.ID == ""
+		if (openOnly && (resolved || untracked)) || (closedOnly && !resolved) {
+			continue
+		}
+		shown++
+		id := r.ID
+		status := "unresolved"
+		if untracked {
+			id = "(no id)"
+			status = "untracked, recorded before IDs"
+		}
+		if resolved {
+			status = r.Verdict
+		}
+		if r.Active {
+			status += ", binding"
+		}
+		b.WriteString(fmt.Sprintf("%s [%s] %s\n", id, status, r.Binding))
+		b.WriteString(fmt.Sprintf("  Falsifier: %s\n", r.Falsifier))
+		if r.Stratagem != "" {
+			b.WriteString(fmt.Sprintf("  Made in: %s\n", r.Stratagem))
+		}
+		if r.Evidence != "" {
+			b.WriteString(fmt.Sprintf("  Evidence: %s\n", r.Evidence))
+		}
+	}
+	if shown == 0 {
+		return "No commitments."
+	}
+	return b.String()
 }
 
 func applyCommitment(s *State, binding, stakes, falsifier string) {
+	id := OpenConstraint(s, "commitment", binding)
+	params := map[string]string{
+		"id":        id,
+		"binding":   binding,
+		"stakes":    stakes,
+		"falsifier": falsifier,
+	}
+	if s.Stratagem != nil {
+		params["stratagem"] = s.Stratagem.Name
+	}
 	s.AddHistory(HistoryEntry{
 		Action: "commitment",
-		Params: map[string]string{
-			"binding":   binding,
-			"stakes":    stakes,
-			"falsifier": falsifier,
-		},
+		Params: params,
 	})
 }
@@ -58,3 +58,170 @@ func TestCommitmentAppendsHistory(t *testing.T) {
 		t.Errorf("falsifier not stored; got %q", h.Params["falsifier"])
 	}
 }
+
+func TestCommitmentRecordsStratagem(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "pivot", false)
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+	h := s.History[len(s.History)-1]
+	if h.Params["stratagem"] != "pivot" {
+		t.Errorf("expected stratagem=pivot, got %q", h.Params["stratagem"])
+	}
+}
+
+func TestResolveCommitmentHeld(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+	id := s.Constraints[0].ID
+
+	r, err := ResolveCommitment(s, s.History, id, VerdictHeld, "A never happened")
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if r.Verdict != VerdictHeld || r.Evidence != "A never happened" {
+		t.Errorf("unexpected record: %+v", r)
+	}
+	if len(s.Constraints) != 0 {
+		t.Error("resolving should close the active binding")
+	}
+
+	records := CollectCommitments(s, s.History)
+	if len(records) != 1 || records[0].Verdict != VerdictHeld || records[0].Active {
+		t.Errorf("expected one held, inactive commitment, got %+v", records)
+	}
+}
+
+func TestResolveCommitmentAfterBindingClosed(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+	applyRitual(s, "seal", []string{"one"}, "sealed")
+	if len(s.Constraints) != 0 {
+		t.Fatal("ritual should have closed the binding")
+	}
+	if UnresolvedCommitments(s, s.History) != 1 {
+		t.Fatalf("closed binding should still be unresolved, got %d", UnresolvedCommitments(s, s.History))
+	}
+	if _, err := ResolveCommitment(s, s.History, "", VerdictFalsified, "A happened"); err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if UnresolvedCommitments(s, s.History) != 0 {
+		t.Error("expected no unresolved commitments")
+	}
+}
+
+func TestResolveCommitmentTwiceFails(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+	id := s.Constraints[0].ID
+	ResolveCommitment(s, s.History, id, VerdictHeld, "ok")
+	if _, err := ResolveCommitment(s, s.History, id, VerdictFalsified, "changed my mind"); err == nil {
+		t.Error("expected error resolving twice")
+	}
+}
+
+func TestResolveCommitmentUnknownID(t *testing.T) {
+	s := NewState()
+	if _, err := ResolveCommitment(s, s.History, "commitment-nope", VerdictHeld, "x"); err == nil {
+		t.Error("expected error for unknown commitment")
+	}
+	if _, err := ResolveCommitment(s, s.History, "", VerdictHeld, "x"); err == nil {
+		t.Error("expected error with nothing to resolve")
+	}
+}
+
+func TestFormatCommitmentListFilters(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "first", "s", "f1")
+	applyCommitment(s, "second", "s", "f2")
+	ResolveCommitment(s, s.History, s.Constraints[0].ID, VerdictHeld, "fine")
+
+	records := CollectCommitments(s, s.History)
+	all := FormatCommitmentList(records, false, false)
+	if !strings.Contains(all, "[held] first") || !strings.Contains(all, "[unresolved, binding] second") {
+		t.Errorf("unexpected list:\n%s", all)
+	}
+	open := FormatCommitmentList(records, true, false)
+	if strings.Contains(open, "first") || !strings.Contains(open, "second") {
+		t.Errorf("--open should only show unresolved:\n%s", open)
+	}
+	closed := FormatCommitmentList(records, false, true)
+	if !strings.Contains(closed, "first") || strings.Contains(closed, "second") {
+		t.Errorf("--closed should only show resolved:\n%s", closed)
+	}
+}
+
+func TestCommitmentReminder(t *testing.T) {
+	s := NewState()
+	if CommitmentReminder(s, s.History) != "" {
+		t.Error("expected no reminder without commitments")
+	}
+	applyCommitment(s, "X", "s", "f")
+	if !strings.Contains(CommitmentReminder(s, s.History), "1 unresolved commitments") {
+		t.Errorf("expected reminder, got %q", CommitmentReminder(s, s.History))
+	}
+}
+
+func TestFormatCommitmentAudit(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "pivot", false)
+	applyCommitment(s, "a", "s", "f")
+	ResolveCommitment(s, s.History, "", VerdictHeld, "ok")
+	AbortStratagem(s)
+	applyCommitment(s, "b", "s", "f")
+	ResolveCommitment(s, s.History, "", VerdictFalsified, "nope")
+	applyCommitment(s, "c", "s", "f")
+
+	out := FormatCommitmentAudit(s, s.History)
+	for _, want := range []string{
+		"Kept: 50% (1 held, 1 falsified)",
+		"Unresolved: 1",
+		"pivot: 1 held, 0 falsified, 0 unresolved",
+		"freestyle: 0 held, 1 falsified, 1 unresolved",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("audit missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLegacyCommitmentNotUnresolved(t *testing.T) {
+	s := NewState()
+	// Recorded before commitments carried IDs
+	s.AddHistory(HistoryEntry{Action: "commitment", Params: map[string]string{"binding": "old", "falsifier": "f"}})
+
+	if UnresolvedCommitments(s, s.History) != 0 || CommitmentReminder(s, s.History) != "" {
+		t.Error("a commitment without an ID can't be resolved and shouldn't be reminded about")
+	}
+	if FormatCommitmentAudit(s, s.History) != "" {
+		t.Error("a commitment without an ID should be left out of the audit")
+	}
+	records := CollectCommitments(s, s.History)
+	if out := FormatCommitmentList(records, true, false); out != "No commitments." {
+		t.Errorf("--open should not list untracked commitments, got:\n%s", out)
+	}
+	if out := FormatCommitmentList(records, false, false); !strings.Contains(out, "(no id) [untracked, recorded before IDs] old") {
+		t.Errorf("expected the untracked commitment in the full list, got:\n%s", out)
+	}
+}
+
+func TestResolveArchivedCommitment(t *testing.T) {
+	old := NewState()
+	applyCommitment(old, "archived", "s", "f")
+	id := old.History[0].Params["id"]
+	archived := old.History
+
+	s := NewState()
+	applyFeel(s, "chest", "warm", "o", "")
+	history := append(append([]HistoryEntry{}, archived...), s.History...)
+
+	if UnresolvedCommitments(s, history) != 1 {
+		t.Fatal("expected the archived commitment to count as unresolved")
+	}
+	if _, err := ResolveCommitment(s, history, id, VerdictHeld, "held up"); err != nil {
+		t.Fatalf("resolve archived commitment: %v", err)
+	}
+	history = append(append([]HistoryEntry{}, archived...), s.History...)
+	if UnresolvedCommitments(s, history) != 0 {
+		t.Error("expected the archived commitment resolved")
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config holds user settings from $METACOG_HOME/config.json. Every field is
+// optional; a missing file means defaults throughout.
+type Config struct {
+	Inspire InspireConfig `json:"inspire"`
+	Session SessionConfig `json:"session"`
+}
+
+type InspireConfig struct {
+	// PoolWeights scales how often a pool is drawn. Unlisted pools weigh 1; 0 excludes a pool.
+	PoolWeights map[string]float64 `json:"pool_weights,omitempty"`
+	// SearchEnabled opts in to 'inspire search'. Off by default so agents keep finding their own coordinates.
+	SearchEnabled bool `json:"search_enabled,omitempty"`
+}
+
+type SessionConfig struct {
+	// IdleTimeout is a Go duration such as "8h". A session with no activity for
+	// that long is ended by the next command that writes state; read-only
+	// commands leave it open and 'status' warns. Empty disables auto-ending.
+	IdleTimeout string `json:"idle_timeout,omitempty"`
+}
+
+// IdleTimeoutDuration parses IdleTimeout; 0 means auto-ending is off.
+func (c SessionConfig) IdleTimeoutDuration() (time.Duration, error) {
+	if c.IdleTimeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.IdleTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("session.idle_timeout %q must be a positive duration such as \"8h\"", c.IdleTimeout)
+	}
+	return d, nil
+}
+
+// LoadConfig reads config.json once per StateManager, which lives for one
+// command, so repeated state writes don't re-read it.
+func (sm *StateManager) LoadConfig() (*Config, error) {
+	if sm.config != nil {
+		return sm.config, nil
+	}
+	var c Config
+	data, err := os.ReadFile(sm.configPath)
+	if os.IsNotExist(err) {
+		sm.config = &c
+		return &c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("config %s is not valid JSON: %w", sm.configPath, err)
+	}
+	for pool, w := range c.Inspire.PoolWeights {
+		if w < 0 {
+			return nil, fmt.Errorf("config %s: weight for pool %q must not be negative", sm.configPath, pool)
+		}
+	}
+	if _, err := c.Session.IdleTimeoutDuration(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", sm.configPath, err)
+	}
+	sm.config = &c
+	return &c, nil
+}
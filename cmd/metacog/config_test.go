@@ -0,0 +1,142 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigMissing(t *testing.T) {
+	sm := NewStateManager(t.TempDir())
+	c, err := sm.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Inspire.PoolWeights) != 0 {
+		t.Errorf("expected empty config, got %+v", c)
+	}
+}
+
+func TestLoadConfigPoolWeights(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"inspire":{"pool_weights":{"philosophy":2.5}}}`), 0644)
+	c, err := NewStateManager(dir).LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Inspire.PoolWeights["philosophy"] != 2.5 {
+		t.Errorf("expected weight 2.5, got %v", c.Inspire.PoolWeights)
+	}
+}
+
+func TestLoadConfigRejectsNegativeWeight(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"inspire":{"pool_weights":{"philosophy":-1}}}`), 0644)
+	if _, err := NewStateManager(dir).LoadConfig(); err == nil {
+		t.Error("expected error for negative weight")
+	}
+}
+
+func TestLoadConfigCorrupted(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{nope`), 0644)
+	if _, err := NewStateManager(dir).LoadConfig(); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestLoadConfigIdleTimeout(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"session":{"idle_timeout":"8h"}}`), 0644)
+	c, err := NewStateManager(dir).LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := c.Session.IdleTimeoutDuration(); d != 8*time.Hour {
+		t.Errorf("expected 8h, got %v", d)
+	}
+
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"session":{"idle_timeout":"eight hours"}}`), 0644)
+	if _, err := NewStateManager(dir).LoadConfig(); err == nil {
+		t.Error("expected error for an unparseable idle timeout")
+	}
+}
+
+func TestLoadLeavesIdleSession(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+	sm.SaveWithLock(func(s *State) error {
+		StartSession(s, "crashed")
+		s.AddHistory(HistoryEntry{Action: "become", Timestamp: "2020-01-01T00:00:00Z", Params: map[string]string{"name": "Ada"}})
+		return nil
+	})
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"session":{"idle_timeout":"1h"}}`), 0644)
+	before, _ := os.ReadFile(filepath.Join(dir, "state.json"))
+
+	s, err := sm.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Session != "crashed" {
+		t.Errorf("reading state should not end the idle session, got %q", s.Session)
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, "state.json"))
+	if string(before) != string(after) {
+		t.Error("reading state should not write it")
+	}
+}
+
+func TestSaveWithLockEndsIdleSession(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+	sm.SaveWithLock(func(s *State) error {
+		StartSession(s, "crashed")
+		s.AddHistory(HistoryEntry{Action: "become", Timestamp: "2020-01-01T00:00:00Z", Params: map[string]string{"name": "Ada"}})
+		return nil
+	})
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"session":{"idle_timeout":"1h"}}`), 0644)
+
+	var during string
+	err := sm.SaveWithLock(func(s *State) error {
+		during = s.Session
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if during != "" {
+		t.Errorf("idle session should be ended before the write, got %q", during)
+	}
+	s, _ := sm.Load()
+	var ended bool
+	for _, h := range s.History {
+		if h.Action == "session" && h.Params["event"] == "ended" && h.Params["reason"] == "idle" {
+			ended = true
+		}
+	}
+	if !ended {
+		t.Error("the idle end should be saved")
+	}
+	if _, err := os.Stat(retrospectivePath(sm, "crashed")); err != nil {
+		t.Errorf("idle end should write a retrospective: %v", err)
+	}
+}
+
+func TestLoadConfigReadsOncePerManager(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"session":{"idle_timeout":"1h"}}`), 0644)
+	sm := NewStateManager(dir)
+	if _, err := sm.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"session":{"idle_timeout":"2h"}}`), 0644)
+	c, _ := sm.LoadConfig()
+	if d, _ := c.Session.IdleTimeoutDuration(); d != time.Hour {
+		t.Errorf("expected the first read to be reused, got %s", d)
+	}
+	c, _ = NewStateManager(dir).LoadConfig()
+	if d, _ := c.Session.IdleTimeoutDuration(); d != 2*time.Hour {
+		t.Errorf("a new manager should read the file again, got %s", d)
+	}
+}
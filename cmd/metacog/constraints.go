@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Boundary events that can close an active constraint.
+const (
+	BoundaryRitual    = "ritual"
+	BoundaryRegister  = "register"
+	BoundaryChord     = "chord"
+	BoundaryFork      = "fork"
+	BoundaryStratagem = "stratagem"
+)
+
+// constraintClosers maps each binding primitive to the boundary events that
+// close it, as promised in the primitive's own output text.
+var constraintClosers = map[string][]string{
+	"commitment": {BoundaryRitual, BoundaryStratagem},
+	"register":   {BoundaryRegister, BoundaryStratagem},
+	"chord":      {BoundaryChord, BoundaryFork, BoundaryStratagem},
+	"silence":    {BoundaryStratagem},
+}
+
+type Constraint struct {
+	ID        string   `json:"id"`
+	Kind      string   `json:"kind"`
+	Summary   string   `json:"summary"`
+	ClosesOn  []string `json:"closes_on"`
+	OpenedAt  string   `json:"opened_at"`
+	Stratagem string   `json:"stratagem,omitempty"`
+}
+
+// OpenConstraint adds a binding to the active ledger and returns its ID.
+func OpenConstraint(s *State, kind, summary string) string {
+	c := Constraint{
+		ID:       fmt.Sprintf("%s-%s", kind, uuid.New().String()[:8]),
+		Kind:     kind,
+		Summary:  summary,
+		ClosesOn: append([]string{}, constraintClosers[kind]...),
+		OpenedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if s.Stratagem != nil {
+		c.Stratagem = s.Stratagem.Name
+	}
+	s.Constraints = append(s.Constraints, c)
+	return c.ID
+}
+
+// CloseConstraintsOn closes every active constraint whose closing rules include event.
+func CloseConstraintsOn(s *State, event string) []Constraint {
+	var closed, remaining []Constraint
+	for _, c := range s.Constraints {
+		if containsString(c.ClosesOn, event) {
+			closed = append(closed, c)
+		} else {
+			remaining = append(remaining, c)
+		}
+	}
+	s.Constraints = remaining
+	for _, c := range closed {
+		recordConstraintClosed(s, c, "boundary", fmt.Sprintf("%s boundary", event))
+	}
+	return closed
+}
+
+// FindConstraint matches an active constraint by ID, or by kind (most recent wins).
+func FindConstraint(s *State, ref string) (int, bool) {
+	for i, c := range s.Constraints {
+		if c.ID == ref {
+			return i, true
+		}
+	}
+	for i := len(s.Constraints) - 1; i >= 0; i-- {
+		if s.Constraints[i].Kind == ref {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// CloseConstraint closes one active constraint explicitly, recording how and why.
+func CloseConstraint(s *State, ref, closedBy, reason string) (Constraint, error) {
+	idx, ok := FindConstraint(s, ref)
+	if !ok {
+		return Constraint{}, fmt.Errorf("no active constraint %q", ref)
+	}
+	c := s.Constraints[idx]
+	s.Constraints = append(append([]Constraint{}, s.Constraints[:idx]...), s.Constraints[idx+1:]...)
+	recordConstraintClosed(s, c, closedBy, reason)
+	return c, nil
+}
+
+func recordConstraintClosed(s *State, c Constraint, closedBy, reason string) {
+	params := map[string]string{
+		"id":        c.ID,
+		"kind":      c.Kind,
+		"event":     "closed",
+		"closed_by": closedBy,
+	}
+	if reason != "" {
+		params["reason"] = reason
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "constraint",
+		Params: params,
+	})
+}
+
+func FormatConstraints(s *State) string {
+	if len(s.Constraints) == 0 {
+		return "Binding: (none)\n"
+	}
+	var b strings.Builder
+	b.WriteString("Binding:\n")
+	for _, c := range s.Constraints {
+		b.WriteString(fmt.Sprintf("  [%s] %s: %s\n", c.ID, c.Kind, c.Summary))
+		b.WriteString(fmt.Sprintf("    Closes on: %s\n", strings.Join(c.ClosesOn, ", ")))
+	}
+	return b.String()
+}
+
+func containsString(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func closedConstraintEvents(s *State) []HistoryEntry {
+	var closed []HistoryEntry
+	for _, h := range s.History {
+		if h.Action == "constraint" && h.Params["event"] == "closed" {
+			closed = append(closed, h)
+		}
+	}
+	return closed
+}
+
+func TestCommitmentOpensConstraint(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+
+	if len(s.Constraints) != 1 {
+		t.Fatalf("expected 1 active constraint, got %d", len(s.Constraints))
+	}
+	c := s.Constraints[0]
+	if c.Kind != "commitment" || c.Summary != "X is true" {
+		t.Errorf("unexpected constraint: %+v", c)
+	}
+	if !strings.HasPrefix(c.ID, "commitment-") {
+		t.Errorf("expected commitment- prefixed ID, got %s", c.ID)
+	}
+	if s.History[len(s.History)-1].Params["id"] != c.ID {
+		t.Error("commitment history entry should carry the constraint ID")
+	}
+}
+
+func TestRitualClosesCommitment(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+	applyRegister(s, "academic", "vernacular", "audience shift")
+	applyRitual(s, "seal", []string{"one"}, "sealed")
+
+	if len(s.Constraints) != 1 || s.Constraints[0].Kind != "register" {
+		t.Fatalf("expected only register to survive a ritual, got %+v", s.Constraints)
+	}
+	closed := closedConstraintEvents(s)
+	if len(closed) != 1 || closed[0].Params["kind"] != "commitment" || closed[0].Params["reason"] != "ritual boundary" {
+		t.Errorf("expected commitment closed at ritual boundary, got %+v", closed)
+	}
+}
+
+func TestRegisterReplacesPreviousRegister(t *testing.T) {
+	s := NewState()
+	applyRegister(s, "academic", "vernacular", "first")
+	applyRegister(s, "vernacular", "oracular", "second")
+
+	if len(s.Constraints) != 1 {
+		t.Fatalf("expected one register constraint, got %d", len(s.Constraints))
+	}
+	if s.Constraints[0].Summary != "vernacular -> oracular" {
+		t.Errorf("expected the newer register to be active, got %s", s.Constraints[0].Summary)
+	}
+}
+
+func TestForkClosesChord(t *testing.T) {
+	s := NewState()
+	applyChord(s, []string{"a", "b"}, "target")
+	applyFork(s, []string{"t1", "t2"}, "vector", "sacrifice")
+
+	if len(s.Constraints) != 0 {
+		t.Errorf("expected chord closed by fork, got %+v", s.Constraints)
+	}
+}
+
+func TestStratagemBoundaryClosesEverything(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "envoy", false)
+	applyRegister(s, "academic", "vernacular", "imposed surface")
+	applySilence(s, "the thing", "premature", "until done")
+	if len(s.Constraints) != 2 {
+		t.Fatalf("expected 2 constraints inside stratagem, got %d", len(s.Constraints))
+	}
+	if s.Constraints[0].Stratagem != "envoy" {
+		t.Errorf("expected constraint to record its stratagem, got %q", s.Constraints[0].Stratagem)
+	}
+
+	AbortStratagem(s)
+	if len(s.Constraints) != 0 {
+		t.Errorf("expected all constraints closed at stratagem boundary, got %+v", s.Constraints)
+	}
+}
+
+func TestReleaseClosesConstraintByKind(t *testing.T) {
+	s := NewState()
+	applySilence(s, "the thing", "premature", "until done")
+
+	out, err := Release(s, "silence", false, "ready to speak")
+	if err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if !strings.Contains(out, "silence") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	closed := closedConstraintEvents(s)
+	if len(closed) != 1 || closed[0].Params["closed_by"] != "release" || closed[0].Params["reason"] != "ready to speak" {
+		t.Errorf("expected release recorded with reason, got %+v", closed)
+	}
+}
+
+func TestReleaseClosesConstraintByID(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "first", "s", "f")
+	applyCommitment(s, "second", "s", "f")
+	id := s.Constraints[0].ID
+
+	if _, err := Release(s, id, false, ""); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if len(s.Constraints) != 1 || s.Constraints[0].Summary != "second" {
+		t.Errorf("expected only the second commitment active, got %+v", s.Constraints)
+	}
+}
+
+func TestFalsifyCommitment(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+
+	c, err := ResolveCommitment(s, s.History, "", VerdictFalsified, "A happened")
+	if err != nil {
+		t.Fatalf("falsify failed: %v", err)
+	}
+	if c.Binding != "X is true" {
+		t.Errorf("unexpected constraint: %+v", c)
+	}
+	closed := closedConstraintEvents(s)
+	if len(closed) != 1 || closed[0].Params["closed_by"] != "falsified" || closed[0].Params["reason"] != "A happened" {
+		t.Errorf("expected falsified closure, got %+v", closed)
+	}
+}
+
+func TestFalsifyRejectsNonCommitment(t *testing.T) {
+	s := NewState()
+	applyRegister(s, "academic", "vernacular", "r")
+	if _, err := ResolveCommitment(s, s.History, s.Constraints[0].ID, VerdictFalsified, "nope"); err == nil {
+		t.Error("expected error falsifying a register constraint")
+	}
+}
+
+func TestFormatStatusListsConstraints(t *testing.T) {
+	s := NewState()
+	applyCommitment(s, "X is true", "credibility", "if A then wrong")
+	out := FormatStatus(s)
+	if !strings.Contains(out, "Binding:") || !strings.Contains(out, "X is true") || !strings.Contains(out, "Closes on: ritual, stratagem") {
+		t.Errorf("status should list active constraints:\n%s", out)
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Draw modes for picking a stance across pools.
+const (
+	// DrawUniformPool picks a pool uniformly, then a stance within it. Small pools are over-sampled.
+	DrawUniformPool = "uniform-pool"
+	// DrawUniformStance weights every stance equally, so large pools are drawn in proportion to size.
+	DrawUniformStance = "uniform-stance"
+)
+
+type DrawOptions struct {
+	Pool string
+	Mode string
+	// Avoid holds lowercased Who names that must not be drawn.
+	Avoid map[string]bool
+	// Weights scales pools; unlisted pools weigh 1.
+	Weights map[string]float64
+	// StanceWeights scales stances within a named pool by lowercased Who; unlisted stances weigh 1.
+	StanceWeights map[string]float64
+}
+
+func (o DrawOptions) weight(pool string) float64 {
+	if w, ok := o.Weights[pool]; ok {
+		return w
+	}
+	return 1
+}
+
+func (o DrawOptions) eligible(pool StancePool) []Stance {
+	if len(o.Avoid) == 0 {
+		return pool.Stances
+	}
+	var out []Stance
+	for _, st := range pool.Stances {
+		if !o.Avoid[strings.ToLower(st.Who)] {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// DrawStance picks a stance according to opts. With default options it
+// consumes r exactly as the original uniform-pool draw did, so seeds recorded
+// before weights and modes existed still reproduce.
+func DrawStance(r *rand.Rand, pools map[string]StancePool, opts DrawOptions) (*Stance, string, error) {
+	if opts.Mode == "" {
+		opts.Mode = DrawUniformPool
+	}
+	if opts.Mode != DrawUniformPool && opts.Mode != DrawUniformStance {
+		return nil, "", fmt.Errorf("unknown draw mode %q. Use %s or %s", opts.Mode, DrawUniformPool, DrawUniformStance)
+	}
+
+	if opts.Pool != "" {
+		pool, ok := pools[opts.Pool]
+		if !ok {
+			return nil, "", fmt.Errorf("unknown pool %q. Use --list to see available pools", opts.Pool)
+		}
+		if len(pool.Stances) == 0 {
+			return nil, "", fmt.Errorf("pool %q has no stances", opts.Pool)
+		}
+		candidates := opts.eligible(pool)
+		if len(candidates) == 0 {
+			return nil, "", fmt.Errorf("every stance in pool %q was drawn or inhabited recently. Loosen --no-repeat-within or drop --avoid-recent-identities", opts.Pool)
+		}
+		if len(opts.StanceWeights) == 0 {
+			s := candidates[r.Intn(len(candidates))]
+			return &s, opts.Pool, nil
+		}
+		weightOf := func(st Stance) float64 {
+			if w, ok := opts.StanceWeights[strings.ToLower(st.Who)]; ok {
+				return w
+			}
+			return 1
+		}
+		total := 0.0
+		for _, st := range candidates {
+			total += weightOf(st)
+		}
+		target := r.Float64() * total
+		for _, st := range candidates {
+			if target < weightOf(st) {
+				return &st, opts.Pool, nil
+			}
+			target -= weightOf(st)
+		}
+		s := candidates[len(candidates)-1]
+		return &s, opts.Pool, nil
+	}
+
+	type candidatePool struct {
+		name    string
+		stances []Stance
+		weight  float64
+	}
+	var candidates []candidatePool
+	uniform := true
+	hadStances := false
+	for _, name := range ListPoolNames(pools) {
+		if len(pools[name].Stances) > 0 {
+			hadStances = true
+		}
+		stances := opts.eligible(pools[name])
+		w := opts.weight(name)
+		if len(stances) == 0 || w == 0 {
+			continue
+		}
+		if w != 1 {
+			uniform = false
+		}
+		candidates = append(candidates, candidatePool{name, stances, w})
+	}
+	if len(candidates) == 0 {
+		if !hadStances {
+			return nil, "", fmt.Errorf("no stance pools loaded")
+		}
+		return nil, "", fmt.Errorf("no eligible stances: every pool is excluded by weight or recent-draw filters")
+	}
+
+	if opts.Mode == DrawUniformStance {
+		total := 0.0
+		for _, c := range candidates {
+			total += c.weight * float64(len(c.stances))
+		}
+		var target float64
+		if uniform {
+			target = float64(r.Intn(int(total)))
+		} else {
+			target = r.Float64() * total
+		}
+		for _, c := range candidates {
+			span := c.weight * float64(len(c.stances))
+			if target < span {
+				s := c.stances[int(target/c.weight)]
+				return &s, c.name, nil
+			}
+			target -= span
+		}
+		last := candidates[len(candidates)-1]
+		s := last.stances[len(last.stances)-1]
+		return &s, last.name, nil
+	}
+
+	chosen := candidates[len(candidates)-1]
+	if uniform {
+		chosen = candidates[r.Intn(len(candidates))]
+	} else {
+		total := 0.0
+		for _, c := range candidates {
+			total += c.weight
+		}
+		target := r.Float64() * total
+		for _, c := range candidates {
+			if target < c.weight {
+				chosen = c
+				break
+			}
+			target -= c.weight
+		}
+	}
+	s := chosen.stances[r.Intn(len(chosen.stances))]
+	return &s, chosen.name, nil
+}
+
+// FilterPools narrows pools to include (when non-empty) and drops exclude.
+// Naming a pool that doesn't exist is an error so typos don't silently widen a draw.
+func FilterPools(pools map[string]StancePool, include, exclude []string) (map[string]StancePool, error) {
+	for _, name := range append(append([]string{}, include...), exclude...) {
+		if _, ok := pools[name]; !ok {
+			return nil, fmt.Errorf("unknown pool %q. Use --list to see available pools", name)
+		}
+	}
+	if len(include) == 0 && len(exclude) == 0 {
+		return pools, nil
+	}
+	out := map[string]StancePool{}
+	if len(include) == 0 {
+		for name, pool := range pools {
+			out[name] = pool
+		}
+	} else {
+		for _, name := range include {
+			out[name] = pools[name]
+		}
+	}
+	for _, name := range exclude {
+		delete(out, name)
+	}
+	return out, nil
+}
+
+type DrawnStance struct {
+	Pool    string `json:"pool"`
+	Who     string `json:"who"`
+	Where   string `json:"where"`
+	Lens    string `json:"lens"`
+	Command string `json:"command"`
+}
+
+// DrawStances draws count stances without repeating one. With distinctPools
+// every stance comes from a different pool, for stratagems that need
+// orthogonal voices.
+func DrawStances(r *rand.Rand, pools map[string]StancePool, opts DrawOptions, count int, distinctPools bool) ([]DrawnStance, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("--count must be at least 1")
+	}
+	if distinctPools && opts.Pool != "" {
+		return nil, fmt.Errorf("--distinct-pools can't be combined with --pool")
+	}
+
+	avoid := map[string]bool{}
+	for who := range opts.Avoid {
+		avoid[who] = true
+	}
+	weights := map[string]float64{}
+	for pool, w := range opts.Weights {
+		weights[pool] = w
+	}
+	opts.Avoid = avoid
+	opts.Weights = weights
+
+	if distinctPools {
+		eligible := 0
+		for name, pool := range pools {
+			if opts.weight(name) > 0 && len(opts.eligible(pool)) > 0 {
+				eligible++
+			}
+		}
+		if eligible < count {
+			return nil, fmt.Errorf("--distinct-pools needs %d pools but only %d are eligible", count, eligible)
+		}
+	}
+
+	var drawn []DrawnStance
+	for len(drawn) < count {
+		stance, pool, err := DrawStance(r, pools, opts)
+		if err != nil {
+			if len(drawn) > 0 {
+				return nil, fmt.Errorf("only %d of %d stances could be drawn: %w", len(drawn), count, err)
+			}
+			return nil, err
+		}
+		drawn = append(drawn, DrawnStance{
+			Pool:    pool,
+			Who:     stance.Who,
+			Where:   stance.Where,
+			Lens:    stance.Lens,
+			Command: becomeCommand(stance),
+		})
+		avoid[strings.ToLower(stance.Who)] = true
+		if distinctPools {
+			weights[pool] = 0
+		}
+	}
+	return drawn, nil
+}
+
+// becomeCommand renders a stance as a ready-to-run become invocation.
+func becomeCommand(s *Stance) string {
+	return fmt.Sprintf("metacog become --name %s --lens %s --env %s", shellQuote(s.Who), shellQuote(s.Lens), shellQuote(s.Where))
+}
+
+func shellQuote(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
+func FormatDrawnStances(drawn []DrawnStance) string {
+	lines := make([]string, 0, len(drawn)*2)
+	for _, d := range drawn {
+		lines = append(lines, fmt.Sprintf("# [%s] %s", d.Pool, d.Who), d.Command)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recentParamValues returns up to limit non-empty values of key from the most
+// recent history entries with the given action, newest first.
+func recentParamValues(s *State, action, key string, limit int) []string {
+	var values []string
+	for i := len(s.History) - 1; i >= 0 && len(values) < limit; i-- {
+		if s.History[i].Action == action {
+			if v := s.History[i].Params[key]; v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// OverRelianceWindow is how many recent becomes/drugs the over-reliance advisory
+// inspects, and how far back --avoid-recent-identities looks.
+const OverRelianceWindow = 20
+
+// RecentDrawAvoidance builds the set of Who names to skip: the last noRepeat
+// inspire draws and, if avoidIdentities is set, recently inhabited identities.
+func RecentDrawAvoidance(s *State, noRepeat int, avoidIdentities bool) map[string]bool {
+	avoid := map[string]bool{}
+	if noRepeat > 0 {
+		for _, who := range recentParamValues(s, "inspire", "who", noRepeat) {
+			avoid[strings.ToLower(who)] = true
+		}
+	}
+	if avoidIdentities {
+		for _, name := range recentParamValues(s, "become", "name", OverRelianceWindow) {
+			avoid[strings.ToLower(name)] = true
+		}
+	}
+	return avoid
+}
+
+// drawFlags are the inspire flags that shape a draw beyond DrawOptions.
+type drawFlags struct {
+	NoRepeat        int
+	AvoidIdentities bool
+	Count           int
+	DistinctPools   bool
+	Pools           []string
+	ExcludePools    []string
+}
+
+// drawParams describes every non-default draw option for the inspire history
+// entry, so a seeded draw can be reproduced with the same flags and config.
+func drawParams(opts DrawOptions, f drawFlags) map[string]string {
+	params := map[string]string{}
+	if opts.Pool != "" {
+		params["from_pool"] = opts.Pool
+	}
+	if opts.Mode != "" && opts.Mode != DrawUniformPool {
+		params["mode"] = opts.Mode
+	}
+	if len(opts.Weights) > 0 {
+		weights := make([]string, 0, len(opts.Weights))
+		for pool, w := range opts.Weights {
+			weights = append(weights, pool+"="+strconv.FormatFloat(w, 'g', -1, 64))
+		}
+		sort.Strings(weights)
+		params["pool_weights"] = strings.Join(weights, ",")
+	}
+	if f.NoRepeat > 0 {
+		params["no_repeat_within"] = strconv.Itoa(f.NoRepeat)
+	}
+	if f.AvoidIdentities {
+		params["avoid_recent_identities"] = "true"
+	}
+	if len(opts.StanceWeights) > 0 {
+		params["weight_by_outcome"] = "true"
+	}
+	if f.Count > 1 {
+		params["count"] = strconv.Itoa(f.Count)
+	}
+	if f.DistinctPools {
+		params["distinct_pools"] = "true"
+	}
+	if len(f.Pools) > 0 {
+		params["pools"] = strings.Join(f.Pools, ",")
+	}
+	if len(f.ExcludePools) > 0 {
+		params["exclude_pools"] = strings.Join(f.ExcludePools, ",")
+	}
+	return params
+}
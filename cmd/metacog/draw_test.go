@@ -0,0 +1,207 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func drawTestPools() map[string]StancePool {
+	return map[string]StancePool{
+		"big": {Name: "big", Stances: []Stance{
+			{Who: "A1"}, {Who: "A2"}, {Who: "A3"}, {Who: "A4"}, {Who: "A5"},
+			{Who: "A6"}, {Who: "A7"}, {Who: "A8"}, {Who: "A9"},
+		}},
+		"small": {Name: "small", Stances: []Stance{{Who: "B1"}}},
+	}
+}
+
+func countPools(t *testing.T, opts DrawOptions, n int) map[string]int {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		_, pool, err := DrawStance(r, drawTestPools(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		counts[pool]++
+	}
+	return counts
+}
+
+func TestDrawStanceDefaultMatchesRandomStanceFrom(t *testing.T) {
+	pools, err := LoadStancePools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		a, pa, _ := RandomStanceFrom(rand.New(rand.NewSource(seed)), pools, "")
+		b, pb, _ := DrawStance(rand.New(rand.NewSource(seed)), pools, DrawOptions{Mode: DrawUniformPool, Weights: map[string]float64{"philosophy": 1}})
+		if *a != *b || pa != pb {
+			t.Errorf("seed %d: default draw diverged: %s/%s vs %s/%s", seed, pa, a.Who, pb, b.Who)
+		}
+	}
+}
+
+func TestDrawStanceUniformStanceFollowsPoolSize(t *testing.T) {
+	counts := countPools(t, DrawOptions{Mode: DrawUniformStance}, 2000)
+	// small holds 1 of 10 stances
+	if counts["small"] < 100 || counts["small"] > 320 {
+		t.Errorf("expected small pool near 10%% under uniform-stance, got %d of 2000", counts["small"])
+	}
+	counts = countPools(t, DrawOptions{}, 2000)
+	if counts["small"] < 850 || counts["small"] > 1150 {
+		t.Errorf("expected small pool near 50%% under uniform-pool, got %d of 2000", counts["small"])
+	}
+}
+
+func TestDrawStanceWeights(t *testing.T) {
+	counts := countPools(t, DrawOptions{Weights: map[string]float64{"small": 3}}, 2000)
+	if counts["small"] < 1350 || counts["small"] > 1650 {
+		t.Errorf("expected small pool near 75%% with weight 3, got %d of 2000", counts["small"])
+	}
+	counts = countPools(t, DrawOptions{Weights: map[string]float64{"small": 0}}, 200)
+	if counts["small"] != 0 {
+		t.Errorf("weight 0 should exclude pool, drew it %d times", counts["small"])
+	}
+}
+
+func TestDrawStanceAvoid(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	avoid := map[string]bool{"b1": true}
+	for i := 0; i < 50; i++ {
+		s, _, err := DrawStance(r, drawTestPools(), DrawOptions{Avoid: avoid})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Who == "B1" {
+			t.Fatal("drew an avoided stance")
+		}
+	}
+	if _, _, err := DrawStance(r, drawTestPools(), DrawOptions{Pool: "small", Avoid: avoid}); err == nil {
+		t.Error("expected error when every stance in the named pool is avoided")
+	}
+	if _, _, err := DrawStance(r, drawTestPools(), DrawOptions{Mode: "bogus"}); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestRecentDrawAvoidance(t *testing.T) {
+	s := NewState()
+	for _, who := range []string{"Old", "Mid", "New"} {
+		s.AddHistory(HistoryEntry{Action: "inspire", Params: map[string]string{"who": who}})
+	}
+	s.AddHistory(HistoryEntry{Action: "become", Params: map[string]string{"name": "Arendt"}})
+
+	avoid := RecentDrawAvoidance(s, 2, false)
+	if !avoid["new"] || !avoid["mid"] || avoid["old"] || avoid["arendt"] {
+		t.Errorf("unexpected avoid set for no-repeat 2: %v", avoid)
+	}
+	avoid = RecentDrawAvoidance(s, 0, true)
+	if !avoid["arendt"] || avoid["new"] {
+		t.Errorf("unexpected avoid set for recent identities: %v", avoid)
+	}
+}
+
+func TestFilterPools(t *testing.T) {
+	pools := drawTestPools()
+	got, err := FilterPools(pools, []string{"small"}, nil)
+	if err != nil || len(got) != 1 || got["small"].Name != "small" {
+		t.Errorf("include filter failed: %v %v", got, err)
+	}
+	got, err = FilterPools(pools, nil, []string{"small"})
+	if err != nil || len(got) != 1 || got["big"].Name != "big" {
+		t.Errorf("exclude filter failed: %v %v", got, err)
+	}
+	if len(pools) != 2 {
+		t.Error("FilterPools must not modify its input")
+	}
+	if _, err := FilterPools(pools, []string{"nope"}, nil); err == nil {
+		t.Error("expected error for unknown pool")
+	}
+}
+
+func TestDrawStancesDistinctPools(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	drawn, err := DrawStances(r, drawTestPools(), DrawOptions{}, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drawn) != 2 || drawn[0].Pool == drawn[1].Pool {
+		t.Errorf("expected two stances from different pools, got %+v", drawn)
+	}
+	if _, err := DrawStances(r, drawTestPools(), DrawOptions{}, 3, true); err == nil {
+		t.Error("expected error when asking for more distinct pools than exist")
+	}
+	if _, err := DrawStances(r, drawTestPools(), DrawOptions{Pool: "big"}, 2, true); err == nil {
+		t.Error("expected error combining --pool with --distinct-pools")
+	}
+}
+
+func TestDrawStancesNoDuplicates(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	drawn, err := DrawStances(r, drawTestPools(), DrawOptions{Pool: "big"}, 9, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, d := range drawn {
+		if seen[d.Who] {
+			t.Errorf("stance %q drawn twice", d.Who)
+		}
+		seen[d.Who] = true
+	}
+}
+
+func TestBecomeCommandQuoting(t *testing.T) {
+	got := becomeCommand(&Stance{Who: "Flann O'Brien", Where: "The Third Policeman", Lens: "bicycles"})
+	want := `metacog become --name 'Flann O'\''Brien' --lens 'bicycles' --env 'The Third Policeman'`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestDrawStanceStanceWeights(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	weights := map[string]float64{"a1": 100}
+	counts := map[string]int{}
+	for i := 0; i < 500; i++ {
+		s, _, err := DrawStance(r, drawTestPools(), DrawOptions{Pool: "big", StanceWeights: weights})
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[s.Who]++
+	}
+	if counts["A1"] < 400 {
+		t.Errorf("expected heavily weighted stance to dominate, got %d of 500", counts["A1"])
+	}
+}
+
+func TestDrawParamsRecordsEveryOption(t *testing.T) {
+	opts := DrawOptions{Pool: "", Mode: DrawUniformStance, Weights: map[string]float64{"b": 0.5, "a": 2}}
+	params := drawParams(opts, drawFlags{
+		Count:         3,
+		DistinctPools: true,
+		Pools:         []string{"a", "b", "c"},
+		ExcludePools:  []string{"d"},
+	})
+	want := map[string]string{
+		"mode":           DrawUniformStance,
+		"pool_weights":   "a=2,b=0.5",
+		"count":          "3",
+		"distinct_pools": "true",
+		"pools":          "a,b,c",
+		"exclude_pools":  "d",
+	}
+	if len(params) != len(want) {
+		t.Errorf("expected %v, got %v", want, params)
+	}
+	for k, v := range want {
+		if params[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, params[k])
+		}
+	}
+	if p := drawParams(DrawOptions{Pool: "personal"}, drawFlags{Count: 1}); len(p) != 1 || p["from_pool"] != "personal" {
+		t.Errorf("a single draw from one pool should record only the pool, got %v", p)
+	}
+}
@@ -49,7 +49,7 @@ func formatDrugs(substance, method, qualia string) string {
 }
 
 func applyDrugs(s *State, substance, method, qualia string) {
-	s.Substrate = &Substrate{Substance: substance, Method: method, Qualia: qualia}
+	s.PushSubstrate(Substrate{Substance: substance, Method: method, Qualia: qualia})
 	s.AddHistory(HistoryEntry{
 		Action: "drugs",
 		Params: map[string]string{"substance": substance, "method": method, "qualia": qualia},
@@ -63,7 +63,7 @@ func formatFork(threads []string, vector, sacrifice string) string {
 	}
 	b.WriteString(fmt.Sprintf("\nDIVERGENCE VECTOR: %s\n", vector))
 	b.WriteString(fmt.Sprintf("SACRIFICE CONDITION: %s\n\n", sacrifice))
-	b.WriteString("Main thread is now in AWAIT state. Do not proceed with primary reasoning until all threads have reported back or been sacrificed. Execute each thread to its conclusion or its sacrifice point. Report findings from each thread separately before reunifying.")
+	b.WriteString("Main thread is now in AWAIT state. Do not proceed with primary reasoning until all threads have reported back or been sacrificed. Execute each thread to its conclusion or its sacrifice point. Report findings from each thread separately before reunifying.\n\nClose each thread with 'metacog thread report <name> --finding ...' or 'metacog thread sacrifice <name> --reason ...'.")
 	return b.String()
 }
 
@@ -76,4 +76,6 @@ func applyFork(s *State, threads []string, vector, sacrifice string) {
 			"sacrifice_condition": sacrifice,
 		},
 	})
+	OpenThreads(s, threads)
+	CloseConstraintsOn(s, BoundaryFork)
 }
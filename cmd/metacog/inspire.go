@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
-	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
-	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -23,9 +23,17 @@ type Stance struct {
 	Lens  string `json:"lens"`
 }
 
+// Where a pool's stances came from.
+const (
+	PoolSourceEmbedded = "embedded"
+	PoolSourceUser     = "user"
+	PoolSourceExtended = "embedded+user"
+)
+
 type StancePool struct {
 	Name    string
 	Stances []Stance
+	Source  string
 }
 
 func LoadStancePools() (map[string]StancePool, error) {
@@ -57,6 +65,7 @@ func LoadStancePools() (map[string]StancePool, error) {
 		pools[name] = StancePool{
 			Name:    name,
 			Stances: stances,
+			Source:  PoolSourceEmbedded,
 		}
 	}
 
@@ -72,33 +81,76 @@ func ListPoolNames(pools map[string]StancePool) []string {
 	return names
 }
 
+// RandomStance draws with an unseeded source. Use RandomStanceFrom for reproducible draws.
 func RandomStance(pools map[string]StancePool, poolName string) (*Stance, string, error) {
-	if poolName != "" {
-		pool, ok := pools[poolName]
-		if !ok {
-			return nil, "", fmt.Errorf("unknown pool %q. Use --list to see available pools", poolName)
-		}
-		if len(pool.Stances) == 0 {
-			return nil, "", fmt.Errorf("pool %q has no stances", poolName)
+	return RandomStanceFrom(rand.New(rand.NewSource(time.Now().UnixNano())), pools, poolName)
+}
+
+// RandomStanceFrom picks a pool, then a stance, from r. Pools are visited in
+// sorted order so a fixed seed over the same pools always yields the same draw.
+func RandomStanceFrom(r *rand.Rand, pools map[string]StancePool, poolName string) (*Stance, string, error) {
+	return DrawStance(r, pools, DrawOptions{Pool: poolName})
+}
+
+// ResolveSeed picks the draw seed: --seed if given, then METACOG_SEED, then the clock.
+// The boolean reports whether the seed was chosen by the caller.
+func ResolveSeed(flagSet bool, flagSeed int64) (int64, bool, error) {
+	if flagSet {
+		return flagSeed, true, nil
+	}
+	if env := os.Getenv("METACOG_SEED"); env != "" {
+		seed, err := strconv.ParseInt(env, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("METACOG_SEED must be an integer, got %q", env)
 		}
-		s := pool.Stances[rand.Intn(len(pool.Stances))]
-		return &s, poolName, nil
+		return seed, true, nil
 	}
+	return time.Now().UnixNano(), false, nil
+}
 
-	names := ListPoolNames(pools)
-	nonEmpty := make([]string, 0, len(names))
-	for _, n := range names {
-		if len(pools[n].Stances) > 0 {
-			nonEmpty = append(nonEmpty, n)
+// recordInspire logs a draw so it can be reproduced from its seed. extra
+// carries any non-default draw options.
+func recordInspire(s *State, pool string, stance *Stance, seed int64, extra map[string]string) {
+	params := map[string]string{
+		"pool": pool,
+		"who":  stance.Who,
+		"seed": strconv.FormatInt(seed, 10),
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "inspire",
+		Params: params,
+	})
+}
+
+// personalSubstrate returns the substrate saved alongside a drawn stance in a
+// user pool, or nil if the stance came from an embedded pool or carries none.
+func personalSubstrate(metacogDir string, d DrawnStance) *Substrate {
+	stances, err := readUserPool(userPoolPath(metacogDir, d.Pool))
+	if err != nil {
+		return nil
+	}
+	for _, ps := range stances {
+		if ps.Who == d.Who && ps.Where == d.Where && ps.Lens == d.Lens && ps.Substance != "" {
+			return &Substrate{Substance: ps.Substance, Method: ps.Method, Qualia: ps.Qualia}
 		}
 	}
-	if len(nonEmpty) == 0 {
-		return nil, "", fmt.Errorf("no stance pools loaded")
+	return nil
+}
+
+// applyInspired becomes a drawn stance verbatim, and takes its saved substrate
+// when one is given. Each history entry records the pool the stance came from.
+func applyInspired(s *State, d DrawnStance, sub *Substrate) string {
+	applyBecome(s, d.Who, d.Lens, d.Where)
+	s.History[len(s.History)-1].Params["pool"] = d.Pool
+	ValidatePrimitiveForStratagem(s, "become")
+	output := formatBecome(d.Who, d.Lens, d.Where)
+	if sub != nil {
+		output += "\n" + applyInspiredSubstrate(s, d.Pool, sub)
 	}
-	chosen := nonEmpty[rand.Intn(len(nonEmpty))]
-	pool := pools[chosen]
-	s := pool.Stances[rand.Intn(len(pool.Stances))]
-	return &s, chosen, nil
+	return output
 }
 
 type PersonalStance struct {
@@ -108,99 +160,143 @@ type PersonalStance struct {
 	Substance string `json:"substance,omitempty"`
 	Method    string `json:"method,omitempty"`
 	Qualia    string `json:"qualia,omitempty"`
+	// Layers beneath the top identity and substrate, bottom first
+	Layers     []Identity  `json:"layers,omitempty"`
+	Substrates []Substrate `json:"substrates,omitempty"`
+	Tags       []string    `json:"tags,omitempty"`
+	// Note tells a teammate why the stance was worth keeping
+	Note string `json:"note,omitempty"`
+	// Provenance: when and where the stance was saved
+	CreatedAt    string `json:"created_at,omitempty"`
+	Session      string `json:"session,omitempty"`
+	SessionID    string `json:"session_id,omitempty"`
+	Stratagem    string `json:"stratagem,omitempty"`
+	StratagemRun string `json:"stratagem_run,omitempty"`
 }
 
-func SavePersonalStance(metacogDir string, s *State) (bool, error) {
-	if s.Identity == nil {
-		return false, fmt.Errorf("no identity set. Use 'metacog become' first")
-	}
-
-	stance := PersonalStance{
-		Who:   s.Identity.Name,
-		Where: s.Identity.Env,
-		Lens:  s.Identity.Lens,
-	}
-	if s.Substrate != nil {
-		stance.Substance = s.Substrate.Substance
-		stance.Method = s.Substrate.Method
-		stance.Qualia = s.Substrate.Qualia
+// sameComposite reports whether two personal stances capture the same layered configuration.
+func (p PersonalStance) sameComposite(other PersonalStance) bool {
+	if p.Who != other.Who || p.Where != other.Where || p.Lens != other.Lens {
+		return false
 	}
-
-	stancesDir := filepath.Join(metacogDir, "stances")
-	os.MkdirAll(stancesDir, 0755)
-	poolPath := filepath.Join(stancesDir, "personal.json")
-	lockPath := filepath.Join(stancesDir, ".personal.lock")
-
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return false, fmt.Errorf("cannot open personal pool lock: %w", err)
+	if len(p.Layers) != len(other.Layers) || len(p.Substrates) != len(other.Substrates) {
+		return false
 	}
-	defer func() {
-		syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
-		lockFile.Close()
-	}()
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
-		return false, fmt.Errorf("cannot acquire personal pool lock: %w", err)
-	}
-
-	var stances []PersonalStance
-	data, readErr := os.ReadFile(poolPath)
-	if readErr != nil && !os.IsNotExist(readErr) {
-		return false, fmt.Errorf("cannot read personal pool %s: %w", poolPath, readErr)
-	}
-	if readErr == nil && len(data) > 0 {
-		if err := json.Unmarshal(data, &stances); err != nil {
-			return false, fmt.Errorf("personal pool %s is corrupted (%w); refusing to overwrite. Move it aside or repair it before saving", poolPath, err)
+	for i := range p.Layers {
+		if p.Layers[i] != other.Layers[i] {
+			return false
 		}
 	}
-
-	for _, existing := range stances {
-		if existing.Who == stance.Who && existing.Where == stance.Where && existing.Lens == stance.Lens {
-			return false, nil
+	for i := range p.Substrates {
+		if p.Substrates[i] != other.Substrates[i] {
+			return false
 		}
 	}
+	return true
+}
 
-	stances = append(stances, stance)
+func SavePersonalStance(metacogDir string, s *State) (bool, error) {
+	return SaveStanceToPool(metacogDir, PersonalPool, s)
+}
 
-	out, err := json.MarshalIndent(stances, "", "  ")
+// SaveStanceToPool appends the current identity composite to a user pool.
+func SaveStanceToPool(metacogDir, pool string, s *State) (bool, error) {
+	stance, err := StanceFromState(s)
 	if err != nil {
-		return false, fmt.Errorf("cannot marshal stances: %w", err)
+		return false, err
+	}
+	return SaveStance(metacogDir, pool, stance)
+}
+
+// StanceFromState captures the current identity and substrate stacks as a personal stance.
+func StanceFromState(s *State) (PersonalStance, error) {
+	identities := s.Identities()
+	if len(identities) == 0 {
+		return PersonalStance{}, fmt.Errorf("no identity set. Use 'metacog become' first")
 	}
 
-	tmpPath := filepath.Join(stancesDir, ".personal.json.tmp")
-	if err := os.WriteFile(tmpPath, out, 0644); err != nil {
-		return false, fmt.Errorf("cannot write temp personal pool: %w", err)
+	top := identities[len(identities)-1]
+	stance := PersonalStance{
+		Who:   top.Name,
+		Where: top.Env,
+		Lens:  top.Lens,
+	}
+	if len(identities) > 1 {
+		stance.Layers = append([]Identity{}, identities[:len(identities)-1]...)
 	}
-	if err := os.Rename(tmpPath, poolPath); err != nil {
-		return false, fmt.Errorf("cannot rename personal pool: %w", err)
+	if substrates := s.Substrates(); len(substrates) > 0 {
+		sub := substrates[len(substrates)-1]
+		stance.Substance = sub.Substance
+		stance.Method = sub.Method
+		stance.Qualia = sub.Qualia
+		if len(substrates) > 1 {
+			stance.Substrates = append([]Substrate{}, substrates[:len(substrates)-1]...)
+		}
 	}
-	return true, nil
+	stance.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	stance.Session = s.Session
+	stance.SessionID = s.SessionID
+	stance.Stratagem, stance.StratagemRun = originatingRun(s, top.Name)
+	return stance, nil
 }
 
-func LoadStancePoolsWithPersonal(metacogDir string) (map[string]StancePool, error) {
-	pools, err := LoadStancePools()
-	if err != nil {
-		return nil, err
+// SaveStance appends stance to a user pool. The personal pool is created on
+// first save; any other pool must already exist (see 'inspire pool create').
+// Saving a composite that is already present merges its tags and note instead,
+// and reports saved as false.
+func SaveStance(metacogDir, pool string, stance PersonalStance) (bool, error) {
+	if err := validatePoolName(pool); err != nil {
+		return false, err
 	}
 
-	poolPath := filepath.Join(metacogDir, "stances", "personal.json")
-	if data, err := os.ReadFile(poolPath); err == nil {
-		var personalStances []PersonalStance
-		if err := json.Unmarshal(data, &personalStances); err == nil && len(personalStances) > 0 {
-			stances := make([]Stance, len(personalStances))
-			for i, ps := range personalStances {
-				stances[i] = Stance{Who: ps.Who, Where: ps.Where, Lens: ps.Lens}
+	saved := false
+	err := withPoolLock(metacogDir, func() error {
+		if pool != PersonalPool && !userPoolExists(metacogDir, pool) {
+			return fmt.Errorf("no user pool %q. Create it with 'metacog inspire pool create %s'", pool, pool)
+		}
+		stances, err := readUserPool(userPoolPath(metacogDir, pool))
+		if err != nil {
+			return err
+		}
+		for i, existing := range stances {
+			if existing.sameComposite(stance) {
+				if len(stance.Tags) == 0 && stance.Note == "" {
+					return nil
+				}
+				stances[i].Tags = mergeTags(existing.Tags, stance.Tags)
+				if stance.Note != "" {
+					stances[i].Note = stance.Note
+				}
+				return writeUserPool(metacogDir, pool, stances)
 			}
-			pools["personal"] = StancePool{Name: "personal", Stances: stances}
 		}
-	}
-
-	return pools, nil
+		if err := writeUserPool(metacogDir, pool, append(stances, stance)); err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
 }
 
 var inspirePoolName string
 var inspireList bool
 var inspireSave bool
+var inspireSeed int64
+var inspireMode string
+var inspireNoRepeat int
+var inspireAvoidRecent bool
+var inspireCount int
+var inspireDistinctPools bool
+var inspirePools []string
+var inspireExcludePools []string
+var inspireApply bool
+var inspireTags []string
+var inspireNote string
+var inspireWeightByOutcome bool
+var inspireKind string
+var inspireSubstratePool string
+var inspireWithSubstrate bool
 
 var inspireCmd = &cobra.Command{
 	Use:   "inspire",
@@ -208,51 +304,351 @@ var inspireCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sm := DefaultStateManager()
 
+		switch inspireKind {
+		case KindIdentity, KindFull:
+		case KindSubstrate:
+			for _, name := range []string{"count", "distinct-pools", "pools", "exclude-pool", "mode", "no-repeat-within", "avoid-recent-identities", "weight-by-outcome", "with-substrate", "substrate-pool", "tag", "note"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s doesn't apply to --kind substrate", name)
+				}
+			}
+			seed, explicit, err := ResolveSeed(cmd.Flags().Changed("seed"), inspireSeed)
+			if err != nil {
+				return err
+			}
+			output, err := runSubstrateInspire(sm, seed, explicit)
+			if err != nil {
+				return err
+			}
+			fmt.Println(FormatOutput(jsonOutput, output, nil))
+			return nil
+		default:
+			return fmt.Errorf("unknown --kind %q. Use %s, %s or %s", inspireKind, KindIdentity, KindSubstrate, KindFull)
+		}
+		if inspireSubstratePool != "" && inspireKind != KindFull {
+			return fmt.Errorf("--substrate-pool needs --kind full")
+		}
+
 		if inspireSave {
 			s, err := sm.Load()
 			if err != nil {
 				return err
 			}
-			saved, err := SavePersonalStance(sm.dir, s)
+			target := inspirePoolName
+			if target == "" {
+				target = PersonalPool
+			}
+			stance, err := StanceFromState(s)
 			if err != nil {
 				return err
 			}
+			stance.Tags = mergeTags(nil, inspireTags)
+			stance.Note = inspireNote
+			saved, err := SaveStance(sm.dir, target, stance)
+			if err != nil {
+				return err
+			}
+			label := s.Identity.Name
+			if len(s.Identities()) > 1 || len(s.Substrates()) > 1 {
+				label = fmt.Sprintf("%s (composite of %d layers)", label, len(s.Identities())+len(s.Substrates()))
+			}
 			var output string
 			if saved {
-				output = fmt.Sprintf("Saved current identity as personal stance: %s", s.Identity.Name)
+				output = fmt.Sprintf("Saved current identity to pool %s: %s", target, label)
+			} else if len(stance.Tags) > 0 || stance.Note != "" {
+				output = fmt.Sprintf("Already saved in pool %s: %s (tags/note updated)", target, label)
 			} else {
-				output = fmt.Sprintf("Already saved as personal stance: %s", s.Identity.Name)
+				output = fmt.Sprintf("Already saved in pool %s: %s", target, label)
 			}
 			fmt.Println(FormatOutput(jsonOutput, output, nil))
 			return nil
 		}
 
-		pools, err := LoadStancePoolsWithPersonal(sm.dir)
+		pools, err := LoadStancePoolsWithUser(sm.dir)
 		if err != nil {
 			return err
 		}
 
 		if inspireList {
 			names := ListPoolNames(pools)
-			output := fmt.Sprintf("%d pools:\n%s", len(names), strings.Join(names, "\n"))
+			lines := make([]string, len(names))
+			for i, name := range names {
+				lines[i] = name
+				if src := pools[name].Source; src != PoolSourceEmbedded {
+					lines[i] = fmt.Sprintf("%s (%s)", name, src)
+				}
+			}
+			output := fmt.Sprintf("%d pools:\n%s", len(names), strings.Join(lines, "\n"))
 			fmt.Println(FormatOutput(jsonOutput, output, nil))
 			return nil
 		}
 
-		stance, pool, err := RandomStance(pools, inspirePoolName)
+		seed, explicit, err := ResolveSeed(cmd.Flags().Changed("seed"), inspireSeed)
+		if err != nil {
+			return err
+		}
+		if inspireNoRepeat < 0 {
+			return fmt.Errorf("--no-repeat-within must not be negative")
+		}
+		cfg, err := sm.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if inspireKind == KindFull && inspireCount != 1 {
+			return fmt.Errorf("--kind full draws a single configuration; drop --count")
+		}
+		var substratePools map[string]SubstratePool
+		if inspireKind == KindFull {
+			if substratePools, err = LoadSubstratePoolsWithUser(sm.dir); err != nil {
+				return err
+			}
+		}
+		if inspireApply && inspireCount != 1 {
+			return fmt.Errorf("--apply takes a single stance; drop --count or run the printed become commands")
+		}
+		if inspireWithSubstrate && !inspireApply {
+			return fmt.Errorf("--with-substrate only makes sense with --apply")
+		}
+		if inspireWeightByOutcome && inspirePoolName == "" {
+			return fmt.Errorf("--weight-by-outcome needs --pool (e.g. --pool personal)")
+		}
+		if inspirePoolName != "" && len(inspirePools) > 0 {
+			return fmt.Errorf("use either --pool or --pools, not both")
+		}
+		pools, err = FilterPools(pools, inspirePools, inspireExcludePools)
 		if err != nil {
 			return err
 		}
+		opts := DrawOptions{
+			Pool:    inspirePoolName,
+			Mode:    inspireMode,
+			Weights: cfg.Inspire.PoolWeights,
+		}
+
+		// The draw happens under the state lock so recent-draw filters see the
+		// same history the new entries are appended to.
+		var drawn []DrawnStance
+		var drawErr error
+		var applied string
+		var fullSub *Substrate
+		var fullSubPool string
+		err = sm.SaveWithLock(func(s *State) error {
+			opts.Avoid = RecentDrawAvoidance(s, inspireNoRepeat, inspireAvoidRecent)
+			if inspireWeightByOutcome {
+				var merged *State
+				merged, drawErr = mergeArchivedHistory(sm, s)
+				if drawErr != nil {
+					return drawErr
+				}
+				opts.StanceWeights = OutcomeWeights(merged.History)
+			}
+			r := rand.New(rand.NewSource(seed))
+			drawn, drawErr = DrawStances(r, pools, opts, inspireCount, inspireDistinctPools)
+			if drawErr != nil {
+				return drawErr
+			}
+			params := drawParams(opts, drawFlags{
+				NoRepeat:        inspireNoRepeat,
+				AvoidIdentities: inspireAvoidRecent,
+				Count:           inspireCount,
+				DistinctPools:   inspireDistinctPools,
+				Pools:           inspirePools,
+				ExcludePools:    inspireExcludePools,
+			})
+			for _, d := range drawn {
+				recordInspire(s, d.Pool, &Stance{Who: d.Who, Where: d.Where, Lens: d.Lens}, seed, params)
+			}
+			if inspireKind == KindFull {
+				fullSub, fullSubPool, drawErr = drawFullSubstrate(r, sm.dir, drawn[0], substratePools)
+				if drawErr != nil {
+					return drawErr
+				}
+				var subParams map[string]string
+				if inspireSubstratePool != "" {
+					subParams = map[string]string{"from_pool": inspireSubstratePool}
+				}
+				recordSubstrateInspire(s, fullSubPool, fullSub, seed, subParams)
+			}
+			if inspireApply {
+				var sub *Substrate
+				if inspireWithSubstrate {
+					sub = personalSubstrate(sm.dir, drawn[0])
+				}
+				applied = applyInspired(s, drawn[0], sub)
+				if fullSub != nil && sub == nil {
+					applied += "\n" + applyInspiredSubstrate(s, fullSubPool, fullSub)
+				}
+			}
+			return nil
+		})
+		if drawErr != nil {
+			return drawErr
+		}
+		if err != nil {
+			if inspireApply {
+				return fmt.Errorf("could not apply drawn stance: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not save state: %v\n", err)
+		}
+		if drawn == nil {
+			// State couldn't be loaded, so the draw never ran. Draw without the
+			// history-based filters; weighting by outcome has nothing to go on.
+			if inspireWeightByOutcome {
+				return err
+			}
+			r := rand.New(rand.NewSource(seed))
+			opts.Avoid = nil
+			if drawn, err = DrawStances(r, pools, opts, inspireCount, inspireDistinctPools); err != nil {
+				return err
+			}
+			if inspireKind == KindFull {
+				if fullSub, fullSubPool, err = drawFullSubstrate(r, sm.dir, drawn[0], substratePools); err != nil {
+					return err
+				}
+			}
+		}
 
-		output := fmt.Sprintf("[%s]\nWho: %s\nWhere: %s\nLens: %s", pool, stance.Who, stance.Where, stance.Lens)
+		if cmd.Flags().Changed("count") {
+			if jsonOutput {
+				data, _ := json.Marshal(drawn)
+				fmt.Println(string(data))
+				return nil
+			}
+			output := FormatDrawnStances(drawn)
+			if explicit {
+				output += fmt.Sprintf("\n# Seed: %d", seed)
+			}
+			fmt.Println(output)
+			return nil
+		}
+
+		d := drawn[0]
+		output := fmt.Sprintf("[%s]\nWho: %s\nWhere: %s\nLens: %s", d.Pool, d.Who, d.Where, d.Lens)
+		if fullSub != nil {
+			output += "\n" + formatSubstrateDraw(fullSubPool, fullSub)
+		}
+		if explicit {
+			output += fmt.Sprintf("\nSeed: %d", seed)
+		}
+		if applied != "" {
+			output += "\n\n" + applied
+		}
 		fmt.Println(FormatOutput(jsonOutput, output, nil))
 		return nil
 	},
 }
 
+// drawFullSubstrate picks the substrate for a --kind full draw. A personal
+// stance keeps the substrate it was saved with.
+func drawFullSubstrate(r *rand.Rand, metacogDir string, d DrawnStance, pools map[string]SubstratePool) (*Substrate, string, error) {
+	if sub := personalSubstrate(metacogDir, d); sub != nil {
+		return sub, d.Pool, nil
+	}
+	return DrawSubstrate(r, pools, inspireSubstratePool)
+}
+
+var inspirePoolCmd = &cobra.Command{
+	Use:     "pool",
+	Aliases: []string{"pools"},
+	Short:   "Manage user stance pools in $METACOG_HOME/stances (--kind substrate: $METACOG_HOME/substrates)",
+}
+
+var inspirePoolKind string
+
+// poolKindDir picks the pool directory for 'inspire pool --kind'.
+func poolKindDir(metacogDir string) (poolDir, error) {
+	switch inspirePoolKind {
+	case KindIdentity:
+		return stancePoolDir(metacogDir), nil
+	case KindSubstrate:
+		return substratePoolDir(metacogDir), nil
+	}
+	return poolDir{}, fmt.Errorf("unknown --kind %q. Use %s or %s", inspirePoolKind, KindIdentity, KindSubstrate)
+}
+
+var inspirePoolCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an empty user pool",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := DefaultStateManager().dir
+		pools, err := poolKindDir(dir)
+		if err != nil {
+			return err
+		}
+		if err := pools.create(dir, args[0]); err != nil {
+			return err
+		}
+		save := "metacog inspire --save --pool " + args[0]
+		if inspirePoolKind == KindSubstrate {
+			save = "metacog inspire --kind substrate --save --pool " + args[0]
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Pool %q created. Save into it with '%s'.", args[0], save), nil))
+		return nil
+	},
+}
+
+var inspirePoolDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a user pool file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := DefaultStateManager().dir
+		pools, err := poolKindDir(dir)
+		if err != nil {
+			return err
+		}
+		if err := pools.delete(dir, args[0]); err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Pool %q deleted.", args[0]), nil))
+		return nil
+	},
+}
+
+var inspirePoolRenameCmd = &cobra.Command{
+	Use:   "rename [old] [new]",
+	Short: "Rename a user pool",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := DefaultStateManager().dir
+		pools, err := poolKindDir(dir)
+		if err != nil {
+			return err
+		}
+		if err := pools.rename(dir, args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Pool %q renamed to %q.", args[0], args[1]), nil))
+		return nil
+	},
+}
+
 func init() {
-	inspireCmd.Flags().StringVar(&inspirePoolName, "pool", "", "Draw from a specific pool")
+	inspireCmd.Flags().StringVar(&inspirePoolName, "pool", "", "Draw from a specific pool (with --save: the user pool to save into, default personal)")
 	inspireCmd.Flags().BoolVar(&inspireList, "list", false, "List available stance pools")
 	inspireCmd.Flags().BoolVar(&inspireSave, "save", false, "Save current identity as a personal stance")
+	inspireCmd.Flags().Int64Var(&inspireSeed, "seed", 0, "Seed pool and stance selection for a reproducible draw (default: $METACOG_SEED, else random)")
+	inspireCmd.Flags().StringVar(&inspireMode, "mode", DrawUniformPool, "Draw mode: uniform-pool (each pool equally likely) or uniform-stance (each stance equally likely)")
+	inspireCmd.Flags().IntVar(&inspireNoRepeat, "no-repeat-within", 0, "Skip stances drawn in the last N inspire draws")
+	inspireCmd.Flags().BoolVar(&inspireAvoidRecent, "avoid-recent-identities", false, "Skip stances whose identity was inhabited in the last 20 becomes")
+	inspireCmd.Flags().IntVar(&inspireCount, "count", 1, "Draw N distinct stances, printed as ready-to-run become commands (JSON: an array)")
+	inspireCmd.Flags().BoolVar(&inspireDistinctPools, "distinct-pools", false, "With --count, draw every stance from a different pool")
+	inspireCmd.Flags().StringSliceVar(&inspirePools, "pools", nil, "Restrict the draw to these pools (comma-separated)")
+	inspireCmd.Flags().StringArrayVar(&inspireExcludePools, "exclude-pool", nil, "Never draw from this pool (repeatable)")
+	inspireCmd.Flags().StringSliceVar(&inspireTags, "tag", nil, "With --save, tag the stance (repeatable or comma-separated)")
+	inspireCmd.Flags().StringVar(&inspireNote, "note", "", "With --save, note why the stance was worth keeping")
+	inspireCmd.Flags().StringVar(&inspireKind, "kind", KindIdentity, "What to draw: identity, substrate (a drugs triple) or full (identity plus substrate)")
+	inspireCmd.Flags().StringVar(&inspireSubstratePool, "substrate-pool", "", "With --kind full, draw the substrate from this pool")
+	inspireCmd.Flags().BoolVar(&inspireWeightByOutcome, "weight-by-outcome", false, "Within --pool, favor identities whose runs were productive")
+	inspireCmd.Flags().BoolVar(&inspireApply, "apply", false, "Become the drawn stance immediately, exactly as drawn")
+	inspireCmd.Flags().BoolVar(&inspireWithSubstrate, "with-substrate", false, "With --apply, also take the substrate saved with a personal stance")
+	for _, c := range []*cobra.Command{inspirePoolCreateCmd, inspirePoolDeleteCmd, inspirePoolRenameCmd} {
+		c.Flags().StringVar(&inspirePoolKind, "kind", KindIdentity, "Which pools: identity (stances) or substrate")
+	}
+	inspirePoolCmd.AddCommand(inspirePoolCreateCmd)
+	inspirePoolCmd.AddCommand(inspirePoolDeleteCmd)
+	inspirePoolCmd.AddCommand(inspirePoolRenameCmd)
+	inspireCmd.AddCommand(inspirePoolCmd)
 	rootCmd.AddCommand(inspireCmd)
 }
@@ -169,7 +169,7 @@ func TestLoadPersonalPool(t *testing.T) {
 	}
 	SavePersonalStance(dir, s)
 
-	pools, err := LoadStancePoolsWithPersonal(dir)
+	pools, err := LoadStancePoolsWithUser(dir)
 	if err != nil {
 		t.Fatalf("load pools failed: %v", err)
 	}
@@ -185,3 +185,44 @@ func TestLoadPersonalPool(t *testing.T) {
 		t.Errorf("expected who=Ada, got %q", pool.Stances[0].Who)
 	}
 }
+
+func TestSavePersonalStanceCapturesComposite(t *testing.T) {
+	dir := t.TempDir()
+
+	s := NewState()
+	applyBecome(s, "Sun Ra", "cosmic jazz", "Arkestra rehearsal")
+	applyBecome(s, "Octavia Butler", "parable", "Pasadena notebook")
+	applyDrugs(s, "caffeine", "antagonism", "sharp")
+	applyDrugs(s, "psilocybin", "5-HT2A", "porous")
+
+	if _, err := SavePersonalStance(dir, s); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "stances", "personal.json"))
+	var stances []PersonalStance
+	json.Unmarshal(data, &stances)
+	if len(stances) != 1 {
+		t.Fatalf("expected 1 stance, got %d", len(stances))
+	}
+	got := stances[0]
+	if got.Who != "Octavia Butler" || got.Substance != "psilocybin" {
+		t.Errorf("expected top layers as who/substance, got %+v", got)
+	}
+	if len(got.Layers) != 1 || got.Layers[0].Name != "Sun Ra" {
+		t.Errorf("expected Sun Ra as lower layer, got %+v", got.Layers)
+	}
+	if len(got.Substrates) != 1 || got.Substrates[0].Substance != "caffeine" {
+		t.Errorf("expected caffeine as lower substrate, got %+v", got.Substrates)
+	}
+
+	// Same top identity over a different stack is a distinct composite
+	s.ReleaseIdentity("Sun Ra")
+	saved, err := SavePersonalStance(dir, s)
+	if err != nil {
+		t.Fatalf("second save failed: %v", err)
+	}
+	if !saved {
+		t.Error("expected a different composite to be saved")
+	}
+}
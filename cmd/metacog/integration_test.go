@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -239,6 +240,94 @@ func TestIntegrationSession(t *testing.T) {
 	}
 }
 
+func TestIntegrationSessionLifecycle(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	// A session that only survives in the history archive
+	archived := `{"action":"session","params":{"name":"old-work","event":"started"},"timestamp":"2025-01-01T10:00:00Z","session":"old-work"}
+{"action":"become","params":{"name":"Ada"},"timestamp":"2025-01-01T10:05:00Z","session":"old-work"}
+{"action":"session","params":{"name":"old-work","event":"ended"},"timestamp":"2025-01-01T11:00:00Z","session":"old-work"}
+`
+	os.WriteFile(filepath.Join(stateDir, "history-archive.jsonl"), []byte(archived), 0644)
+
+	out, err := runMetacog(t, binary, stateDir, "session", "list")
+	if err != nil || !strings.Contains(out, "old-work") {
+		t.Fatalf("session list should include archived sessions: %v\n%s", err, out)
+	}
+	if out, err = runMetacog(t, binary, stateDir, "session", "start", "old-work"); err == nil || !strings.Contains(out, "session resume old-work") {
+		t.Errorf("starting an archived session's name should point to resume: %v\n%s", err, out)
+	}
+
+	if out, err = runMetacog(t, binary, stateDir, "session", "resume", "old-work"); err != nil {
+		t.Fatalf("session resume: %v\n%s", err, out)
+	}
+	if out, err = runMetacog(t, binary, stateDir, "session", "start", "--sub", "sitting"); err != nil {
+		t.Fatalf("session start --sub: %v\n%s", err, out)
+	}
+	runMetacog(t, binary, stateDir, "feel", "--somewhere", "chest", "--quality", "warm", "--sigil", "o")
+	out, _ = runMetacog(t, binary, stateDir, "session", "end")
+	if !strings.Contains(out, `Back in "old-work"`) {
+		t.Errorf("ending a sub-session should return to the parent:\n%s", out)
+	}
+
+	if out, err = runMetacog(t, binary, stateDir, "journal", "written under the old name"); err != nil {
+		t.Fatalf("journal: %v\n%s", err, out)
+	}
+	if out, err = runMetacog(t, binary, stateDir, "session", "rename", "old-work", "engagement"); err != nil {
+		t.Fatalf("session rename: %v\n%s", err, out)
+	}
+	out, _ = runMetacog(t, binary, stateDir, "journal", "list", "--session", "engagement")
+	if !strings.Contains(out, "written under the old name") {
+		t.Errorf("journal list --session should follow the rename:\n%s", out)
+	}
+	out, err = runMetacog(t, binary, stateDir, "session", "show", "engagement")
+	if err != nil {
+		t.Fatalf("session show: %v\n%s", err, out)
+	}
+	for _, want := range []string{`Session "engagement" (active)`, "over 2 sittings", "become 1", "Sub-sessions: sitting"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("session show should contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestIntegrationSessionRetrospective(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	if out, err := runMetacog(t, binary, stateDir, "session", "start", "spike", "--goal", "find the bottleneck"); err != nil {
+		t.Fatalf("session start --goal: %v\n%s", err, out)
+	}
+	runMetacog(t, binary, stateDir, "become", "--name", "Ada", "--lens", "logic", "--env", "lab")
+	runMetacog(t, binary, stateDir, "outcome", "--result", "productive", "--shift", "it was the cache")
+
+	if out, err := runMetacog(t, binary, stateDir, "session", "end", "--goal-met", "nope"); err == nil {
+		t.Errorf("invalid --goal-met should fail:\n%s", out)
+	}
+	out, err := runMetacog(t, binary, stateDir, "session", "end", "--goal-met", "yes", "--shifted", "from guessing to measuring", "--keep", "profile first")
+	if err != nil {
+		t.Fatalf("session end: %v\n%s", err, out)
+	}
+	path := filepath.Join(stateDir, "sessions", "spike.md")
+	if !strings.Contains(out, path) {
+		t.Errorf("end should name the retrospective file:\n%s", out)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("retrospective not written: %v", err)
+	}
+
+	out, err = runMetacog(t, binary, stateDir, "session", "show", "spike")
+	if err != nil {
+		t.Fatalf("session show: %v\n%s", err, out)
+	}
+	for _, want := range []string{"Goal: find the bottleneck", "Goal met: yes", "What shifted: from guessing to measuring", "it was the cache"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("session show should print the retrospective with %q:\n%s", want, out)
+		}
+	}
+}
+
 func TestIntegrationInspireSave(t *testing.T) {
 	binary := buildBinary(t)
 	stateDir := t.TempDir()
@@ -262,6 +351,23 @@ func TestIntegrationInspireSave(t *testing.T) {
 	}
 }
 
+func TestIntegrationInspireWithCorruptState(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+	os.WriteFile(filepath.Join(stateDir, "state.json"), []byte("{not json"), 0644)
+
+	out, err := runMetacog(t, binary, stateDir, "inspire")
+	if err != nil {
+		t.Fatalf("inspire should still draw read-only: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Who:") || !strings.Contains(out, "could not save state") {
+		t.Errorf("expected a stance and a save warning:\n%s", out)
+	}
+	if out, err = runMetacog(t, binary, stateDir, "inspire", "--count", "2"); err != nil || strings.Count(out, "metacog become") != 2 {
+		t.Errorf("inspire --count should still draw: %v\n%s", err, out)
+	}
+}
+
 func TestIntegrationOutcome(t *testing.T) {
 	binary := buildBinary(t)
 	stateDir := t.TempDir()
@@ -332,6 +438,165 @@ func TestIntegrationFreestyleOutcome(t *testing.T) {
 	}
 }
 
+func TestIntegrationOutcomeTarget(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	completeReset := func() {
+		runMetacog(t, binary, stateDir, "stratagem", "start", "reset")
+		runMetacog(t, binary, stateDir, "ritual", "--threshold", "test", "--steps", "s1", "--result", "done")
+		runMetacog(t, binary, stateDir, "stratagem", "next")
+		runMetacog(t, binary, stateDir, "stratagem", "next")
+		runMetacog(t, binary, stateDir, "ritual", "--threshold", "ground", "--steps", "s1", "--result", "done")
+		runMetacog(t, binary, stateDir, "stratagem", "next")
+	}
+	completeReset()
+	runMetacog(t, binary, stateDir, "become", "--name", "Ada", "--lens", "logic", "--env", "lab")
+	completeReset()
+
+	out, err := runMetacog(t, binary, stateDir, "outcome", "pending")
+	if err != nil {
+		t.Fatalf("outcome pending: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "3 awaiting") || !strings.Contains(out, "freestyle burst") {
+		t.Fatalf("expected two runs and a burst pending:\n%s", out)
+	}
+
+	s, _ := NewStateManager(stateDir).Load()
+	firstRun := OutcomeTargets(s.History)[0].Run
+	if out, err = runMetacog(t, binary, stateDir, "outcome", "--target", firstRun, "--result", "productive"); err != nil {
+		t.Fatalf("outcome --target run: %v\n%s", err, out)
+	}
+	if out, err = runMetacog(t, binary, stateDir, "outcome", "--target", "last-freestyle", "--result", "unproductive"); err != nil {
+		t.Fatalf("outcome --target last-freestyle: %v\n%s", err, out)
+	}
+	if _, err = runMetacog(t, binary, stateDir, "outcome", "--target", "bogus", "--result", "productive"); err == nil {
+		t.Error("expected error for an unknown target")
+	}
+
+	out, _ = runMetacog(t, binary, stateDir, "outcome", "pending")
+	if !strings.Contains(out, "1 awaiting") || strings.Contains(out, firstRun) {
+		t.Errorf("only the second run should remain pending:\n%s", out)
+	}
+
+	// Revise the older outcome, not just the latest
+	s, _ = NewStateManager(stateDir).Load()
+	first := CollectOutcomes(s.History)[0]
+	id := strconv.Itoa(first.ID)
+	if out, err = runMetacog(t, binary, stateDir, "outcome", "amend", id, "--result", "unproductive", "--reason", "faded by morning"); err != nil {
+		t.Fatalf("outcome amend: %v\n%s", err, out)
+	}
+	out, _ = runMetacog(t, binary, stateDir, "outcome", "list")
+	if !strings.Contains(out, "#"+id+" [") || !strings.Contains(out, `result "productive" -> "unproductive" (faded by morning)`) {
+		t.Errorf("expected the amendment in the outcome list:\n%s", out)
+	}
+	if _, err = runMetacog(t, binary, stateDir, "outcome", "amend", id); err == nil {
+		t.Error("expected error when amending with no changes")
+	}
+}
+
+func TestIntegrationOutcomeUnfinishedRun(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	runMetacog(t, binary, stateDir, "stratagem", "start", "pivot")
+	runMetacog(t, binary, stateDir, "stratagem", "abort")
+
+	out, _ := runMetacog(t, binary, stateDir, "outcome", "pending")
+	if strings.Contains(out, "pivot") {
+		t.Errorf("an aborted run should not be pending by default:\n%s", out)
+	}
+	out, _ = runMetacog(t, binary, stateDir, "outcome", "pending", "--unfinished")
+	if !strings.Contains(out, "(aborted at step 1)") {
+		t.Errorf("expected the aborted run with --unfinished:\n%s", out)
+	}
+	if out, err := runMetacog(t, binary, stateDir, "outcome", "--target", "last-stratagem", "--result", "productive", "--shift", "saw it at step one"); err != nil {
+		t.Fatalf("outcome on aborted run: %v\n%s", err, out)
+	}
+	out, _ = runMetacog(t, binary, stateDir, "reflect")
+	if !strings.Contains(out, "pivot: 100% (1/1 runs), typically at step 1/5; stopping was productive 1/1") {
+		t.Errorf("expected abandonment in reflect:\n%s", out)
+	}
+}
+
+func TestIntegrationFreestyleSequencePromote(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		runMetacog(t, binary, stateDir, "become", "--name", "Ada", "--lens", "logic", "--env", "lab")
+		runMetacog(t, binary, stateDir, "drugs", "--substance", "caffeine", "--method", "antagonism", "--qualia", "sharp")
+		if out, err := runMetacog(t, binary, stateDir, "outcome", "--result", "productive"); err != nil {
+			t.Fatalf("outcome: %v\n%s", err, out)
+		}
+	}
+
+	out, _ := runMetacog(t, binary, stateDir, "reflect")
+	if !strings.Contains(out, "become → drugs: 100% productive (2/2)") {
+		t.Errorf("reflect should report the recurring sequence:\n%s", out)
+	}
+
+	out, err := runMetacog(t, binary, stateDir, "stratagem", "promote", "become → drugs", "--name", "dose")
+	if err != nil {
+		t.Fatalf("promote: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "productive in 2/2") {
+		t.Errorf("promote should report the sequence's record:\n%s", out)
+	}
+
+	// A fresh process picks the stratagem up from stratagems.json
+	if out, err = runMetacog(t, binary, stateDir, "stratagem", "start", "dose"); err != nil {
+		t.Fatalf("start promoted stratagem: %v\n%s", err, out)
+	}
+	runMetacog(t, binary, stateDir, "become", "--name", "Ada", "--lens", "logic", "--env", "lab")
+	runMetacog(t, binary, stateDir, "stratagem", "next")
+	runMetacog(t, binary, stateDir, "drugs", "--substance", "caffeine", "--method", "antagonism", "--qualia", "sharp")
+	out, _ = runMetacog(t, binary, stateDir, "stratagem", "next")
+	if !strings.Contains(out, "DOSE complete") {
+		t.Errorf("expected the promoted stratagem to complete:\n%s", out)
+	}
+
+	if out, _ = runMetacog(t, binary, stateDir, "version"); !strings.Contains(out, " dose") {
+		t.Errorf("version should list the promoted stratagem:\n%s", out)
+	}
+	for _, sub := range []string{"start", "preview"} {
+		if out, _ = runMetacog(t, binary, stateDir, "__complete", "stratagem", sub, "do"); !strings.Contains(out, "dose") {
+			t.Errorf("%s should complete the promoted stratagem:\n%s", sub, out)
+		}
+	}
+
+	// Losing stratagems.json mid-run is reported, not a crash
+	runMetacog(t, binary, stateDir, "stratagem", "start", "dose")
+	os.Remove(filepath.Join(stateDir, "stratagems.json"))
+	out, err = runMetacog(t, binary, stateDir, "stratagem", "next")
+	if err == nil || !strings.Contains(out, `"dose" is not defined`) {
+		t.Errorf("next without the definition should fail cleanly: %v\n%s", err, out)
+	}
+	if out, err = runMetacog(t, binary, stateDir, "stratagem", "abort"); err != nil {
+		t.Errorf("abort should still work: %v\n%s", err, out)
+	}
+}
+
+func TestIntegrationSubstratePoolCreate(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	if out, err := runMetacog(t, binary, stateDir, "inspire", "pool", "create", "--kind", "substrate", "team"); err != nil {
+		t.Fatalf("pool create: %v\n%s", err, out)
+	}
+	runMetacog(t, binary, stateDir, "drugs", "--substance", "static", "--method", "interference", "--qualia", "grainy")
+	if out, err := runMetacog(t, binary, stateDir, "inspire", "--kind", "substrate", "--save", "--pool", "team"); err != nil {
+		t.Fatalf("save substrate: %v\n%s", err, out)
+	}
+	out, err := runMetacog(t, binary, stateDir, "inspire", "--kind", "substrate", "--pool", "team")
+	if err != nil || !strings.Contains(out, "static") {
+		t.Errorf("expected to draw the saved substrate from team: %v\n%s", err, out)
+	}
+	if out, err := runMetacog(t, binary, stateDir, "inspire", "pool", "delete", "--kind", "substrate", "team"); err != nil {
+		t.Fatalf("pool delete: %v\n%s", err, out)
+	}
+}
+
 func TestIntegrationJournal(t *testing.T) {
 	binary := buildBinary(t)
 	stateDir := t.TempDir()
@@ -385,6 +650,49 @@ func TestIntegrationJournal(t *testing.T) {
 	}
 }
 
+func TestIntegrationJournalQuery(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	runMetacog(t, binary, stateDir, "become", "--name", "Ada", "--lens", "logic", "--env", "lab")
+	runMetacog(t, binary, stateDir, "journal", "--tag", "a", "--tag", "b", "both tags as Ada")
+	runMetacog(t, binary, stateDir, "become", "--name", "Eno", "--lens", "ambient", "--env", "studio")
+	runMetacog(t, binary, stateDir, "journal", "--tag", "a", "only a as Eno")
+
+	out, err := runMetacog(t, binary, stateDir, "journal", "list", "--tag", "a", "--tag", "b", "--all")
+	if err != nil {
+		t.Fatalf("journal list --all: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "both tags") || strings.Contains(out, "only a") {
+		t.Errorf("--all should require both tags:\n%s", out)
+	}
+
+	out, _ = runMetacog(t, binary, stateDir, "journal", "list", "--identity", "eno", "--grep", "only")
+	if !strings.Contains(out, "only a as Eno") || strings.Contains(out, "Ada") {
+		t.Errorf("--identity/--grep should select the Eno entry:\n%s", out)
+	}
+
+	out, _ = runMetacog(t, binary, stateDir, "journal", "list", "--until", "2000-01-01")
+	if !strings.Contains(out, "No journal entries.") {
+		t.Errorf("--until in the past should match nothing:\n%s", out)
+	}
+
+	out, err = runMetacog(t, binary, stateDir, "journal", "tags")
+	if err != nil {
+		t.Fatalf("journal tags: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "2  a") || !strings.Contains(out, "1  b") {
+		t.Errorf("tags should be counted:\n%s", out)
+	}
+
+	f, _ := os.OpenFile(filepath.Join(stateDir, "journal.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("garbage\n")
+	f.Close()
+	if out, err = runMetacog(t, binary, stateDir, "journal", "list", "--strict"); err == nil || !strings.Contains(out, "malformed lines: 3") {
+		t.Errorf("--strict should report line 3:\n%s", out)
+	}
+}
+
 func TestIntegrationJournalWithSession(t *testing.T) {
 	binary := buildBinary(t)
 	stateDir := t.TempDir()
@@ -440,6 +748,67 @@ func TestIntegrationJournalInReflect(t *testing.T) {
 	}
 }
 
+func TestIntegrationJournalEditing(t *testing.T) {
+	binary := buildBinary(t)
+	stateDir := t.TempDir()
+
+	cmd := exec.Command(binary, "journal", "--stdin", "--tag", "long")
+	cmd.Env = append(os.Environ(), "METACOG_HOME="+stateDir)
+	cmd.Stdin = strings.NewReader("first paragraph\n\nsecond paragraph\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("journal --stdin: %v\n%s", err, out)
+	}
+
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	os.WriteFile(editor, []byte("#!/bin/sh\necho 'writen in the editor' > \"$1\"\n"), 0755)
+	cmd = exec.Command(binary, "journal", "--edit")
+	cmd.Env = append(os.Environ(), "METACOG_HOME="+stateDir, "EDITOR="+editor)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("journal --edit: %v\n%s", err, out)
+	}
+
+	entries, err := NewStateManager(stateDir).LoadJournal()
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d (%v)", len(entries), err)
+	}
+	if entries[0].Insight != "first paragraph\n\nsecond paragraph" {
+		t.Errorf("stdin entry should keep paragraphs, got %q", entries[0].Insight)
+	}
+
+	out, err := runMetacog(t, binary, stateDir, "journal", "amend", entries[1].ID, "written in the editor", "--reason", "typo")
+	if err != nil {
+		t.Fatalf("journal amend: %v\n%s", err, out)
+	}
+	out, err = runMetacog(t, binary, stateDir, "journal", "delete", entries[0].ID)
+	if err != nil {
+		t.Fatalf("journal delete: %v\n%s", err, out)
+	}
+
+	out, err = runMetacog(t, binary, stateDir, "journal", "list")
+	if err != nil {
+		t.Fatalf("journal list: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "written in the editor") || strings.Contains(out, "writen") {
+		t.Errorf("list should show the amended text only:\n%s", out)
+	}
+	if strings.Contains(out, "paragraph") {
+		t.Errorf("list should hide the deleted entry:\n%s", out)
+	}
+
+	if out, err = runMetacog(t, binary, stateDir, "journal", "delete", "nope"); err == nil {
+		t.Errorf("deleting an unknown ID should fail:\n%s", out)
+	}
+
+	// Tags alone can be amended without retyping the text
+	if out, err = runMetacog(t, binary, stateDir, "journal", "amend", entries[1].ID, "--tag", "editor"); err != nil {
+		t.Fatalf("journal amend --tag: %v\n%s", err, out)
+	}
+	entries, _ = NewStateManager(stateDir).LoadJournal()
+	if len(entries) != 1 || entries[0].Insight != "written in the editor" || len(entries[0].Tags) != 1 || entries[0].Tags[0] != "editor" {
+		t.Errorf("expected the amended text kept with the new tag, got %+v", entries)
+	}
+}
+
 func TestIntegrationAdvisories(t *testing.T) {
 	binary := buildBinary(t)
 	stateDir := t.TempDir()
@@ -1,53 +1,305 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
+// Journal record operations. The journal is append-only: an amendment or
+// deletion is a new line naming its Target, folded in by LoadJournal.
+const (
+	JournalAmend  = "amend"
+	JournalDelete = "delete"
+)
+
 type JournalEntry struct {
+	ID        string   `json:"id,omitempty"`
 	Timestamp string   `json:"timestamp"`
-	Insight   string   `json:"insight"`
+	Insight   string   `json:"insight,omitempty"`
 	Session   string   `json:"session,omitempty"`
 	Tags      []string `json:"tags,omitempty"`
+	// Context is the practice state the insight was written in; absent on older entries
+	Context *JournalContext `json:"context,omitempty"`
+	// Op, Target and Reason are set on amend/delete records only
+	Op     string `json:"op,omitempty"`
+	Target string `json:"target,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// AmendedAt is filled in by LoadJournal when an amendment was folded in
+	AmendedAt string `json:"amended_at,omitempty"`
+}
+
+func newJournalID() string {
+	return uuid.New().String()[:8]
+}
+
+// legacyJournalID derives an ID for entries written before IDs existed. The
+// journal is never rewritten, so a line's hash is as stable as a stored ID.
+func legacyJournalID(line string) string {
+	sum := sha256.Sum256([]byte(line))
+	return hex.EncodeToString(sum[:4])
 }
 
-func FilterJournal(entries []JournalEntry, tag, session string) []JournalEntry {
-	if tag == "" && session == "" {
+// foldJournal applies amend and delete records to the insights they target,
+// in file order. Records naming an unknown or already-deleted entry are ignored.
+func foldJournal(records []JournalEntry) []JournalEntry {
+	var entries []JournalEntry
+	index := map[string]int{}
+	deleted := map[string]bool{}
+	for _, r := range records {
+		switch r.Op {
+		case "":
+			index[r.ID] = len(entries)
+			entries = append(entries, r)
+		case JournalAmend:
+			i, ok := index[r.Target]
+			if !ok || deleted[r.Target] {
+				continue
+			}
+			entries[i].Insight = r.Insight
+			if r.Tags != nil {
+				entries[i].Tags = r.Tags
+			}
+			entries[i].AmendedAt = r.Timestamp
+		case JournalDelete:
+			if _, ok := index[r.Target]; ok {
+				deleted[r.Target] = true
+			}
+		}
+	}
+	if len(deleted) == 0 {
 		return entries
 	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if !deleted[e.ID] {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func findJournalEntry(entries []JournalEntry, id string) (*JournalEntry, error) {
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no journal entry %q. Run 'metacog journal list' to see entry IDs", id)
+}
+
+// JournalContext snapshots the active layers and stratagem when an insight is
+// recorded. HistoryIndex is the 1-based position of the latest history entry
+// as numbered by 'history --full', or 0 when there was no history yet.
+type JournalContext struct {
+	Identity      *Identity  `json:"identity,omitempty"`
+	Substrate     *Substrate `json:"substrate,omitempty"`
+	Stratagem     string     `json:"stratagem,omitempty"`
+	StratagemStep int        `json:"stratagem_step,omitempty"`
+	StratagemRun  string     `json:"stratagem_run,omitempty"`
+	HistoryIndex  int        `json:"history_index,omitempty"`
+	HistoryAction string     `json:"history_action,omitempty"`
+}
+
+// snapshotJournalContext captures s for a journal entry. history is the
+// merged (archived plus live) history so the index stays stable after trimming.
+func snapshotJournalContext(s *State, history []HistoryEntry) *JournalContext {
+	c := &JournalContext{}
+	if ids := s.Identities(); len(ids) > 0 {
+		id := ids[len(ids)-1]
+		c.Identity = &id
+	}
+	if subs := s.Substrates(); len(subs) > 0 {
+		sub := subs[len(subs)-1]
+		c.Substrate = &sub
+	}
+	if s.Stratagem != nil {
+		c.Stratagem = s.Stratagem.Name
+		c.StratagemStep = s.Stratagem.Step + 1
+		c.StratagemRun = s.Stratagem.RunID
+	}
+	if len(history) > 0 {
+		c.HistoryIndex = len(history)
+		c.HistoryAction = history[len(history)-1].Action
+	}
+	if *c == (JournalContext{}) {
+		return nil
+	}
+	return c
+}
+
+func (c *JournalContext) String() string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	if c.Identity != nil {
+		parts = append(parts, "as "+c.Identity.Name)
+	}
+	if c.Substrate != nil {
+		parts = append(parts, "on "+c.Substrate.Substance)
+	}
+	if c.Stratagem != "" {
+		stratagem := c.Stratagem
+		if def, ok := Stratagems[c.Stratagem]; ok {
+			stratagem = fmt.Sprintf("%s step %d/%d", def.Name, c.StratagemStep, len(def.Steps))
+		} else if c.StratagemStep > 0 {
+			stratagem = fmt.Sprintf("%s step %d", c.Stratagem, c.StratagemStep)
+		}
+		parts = append(parts, stratagem)
+	}
+	if c.HistoryIndex > 0 {
+		parts = append(parts, fmt.Sprintf("after #%d %s", c.HistoryIndex, c.HistoryAction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// JournalFilter selects journal entries. Zero fields match everything.
+type JournalFilter struct {
+	Tags []string
+	// AllTags requires every tag in Tags; otherwise any one suffices
+	AllTags bool
+	// Session matches entries written under the session's current name or,
+	// through Renames, any earlier one
+	Session string
+	Renames []sessionRename
+	// Since and Until bound the entry timestamp, inclusive
+	Since, Until time.Time
+	Grep         *regexp.Regexp
+	// Identity matches the active identity recorded in the entry's context, case-insensitively
+	Identity string
+}
+
+func (f JournalFilter) matchTags(tags []string) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+	has := map[string]bool{}
+	for _, t := range tags {
+		has[t] = true
+	}
+	for _, want := range f.Tags {
+		if has[want] && !f.AllTags {
+			return true
+		}
+		if !has[want] && f.AllTags {
+			return false
+		}
+	}
+	return f.AllTags
+}
+
+func (f JournalFilter) Match(e JournalEntry) bool {
+	if f.Session != "" && resolveSessionAt(f.Renames, e.Session, e.Timestamp) != f.Session {
+		return false
+	}
+	if !f.matchTags(e.Tags) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			return false
+		}
+		if !f.Since.IsZero() && ts.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && ts.After(f.Until) {
+			return false
+		}
+	}
+	if f.Grep != nil && !f.Grep.MatchString(e.Insight) {
+		return false
+	}
+	if f.Identity != "" && (e.Context == nil || e.Context.Identity == nil || !strings.EqualFold(e.Context.Identity.Name, f.Identity)) {
+		return false
+	}
+	return true
+}
+
+func FilterJournal(entries []JournalEntry, f JournalFilter) []JournalEntry {
 	var filtered []JournalEntry
 	for _, e := range entries {
-		if session != "" && e.Session != session {
-			continue
-		}
-		if tag != "" {
-			found := false
-			for _, t := range e.Tags {
-				if t == tag {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
+		if f.Match(e) {
+			filtered = append(filtered, e)
 		}
-		filtered = append(filtered, e)
 	}
 	return filtered
 }
 
-func FormatJournalEntries(entries []JournalEntry) string {
+// parseJournalTime reads a --since/--until value: RFC3339, or a bare
+// YYYY-MM-DD date, which with endOfDay covers the whole day.
+func parseJournalTime(flag, value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s %q: use YYYY-MM-DD or an RFC3339 timestamp", flag, value)
+	}
+	if endOfDay {
+		d = d.Add(24*time.Hour - time.Second)
+	}
+	return d, nil
+}
+
+type tagCount struct {
+	Tag   string
+	Count int
+}
+
+// JournalTagCounts counts entries per tag, most used first.
+func JournalTagCounts(entries []JournalEntry) []tagCount {
+	counts := map[string]int{}
+	for _, e := range entries {
+		for _, t := range e.Tags {
+			counts[t]++
+		}
+	}
+	out := make([]tagCount, 0, len(counts))
+	for t, n := range counts {
+		out = append(out, tagCount{t, n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out
+}
+
+func FormatJournalTags(counts []tagCount) string {
+	if len(counts) == 0 {
+		return "No tagged journal entries."
+	}
+	lines := make([]string, len(counts))
+	for i, c := range counts {
+		lines[i] = fmt.Sprintf("%4d  %s", c.Count, c.Tag)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func FormatJournalEntries(entries []JournalEntry, withContext bool) string {
 	if len(entries) == 0 {
 		return "No journal entries."
 	}
 	var b strings.Builder
 	for i, e := range entries {
-		b.WriteString(fmt.Sprintf("%d. [%s] %s", i+1, e.Timestamp, e.Insight))
+		b.WriteString(fmt.Sprintf("%d. %s [%s] %s", i+1, e.ID, e.Timestamp, indentInsight(e.Insight, "   ")))
+		if e.AmendedAt != "" {
+			b.WriteString(fmt.Sprintf(" (amended %s)", e.AmendedAt))
+		}
 		if e.Session != "" {
 			b.WriteString(fmt.Sprintf(" (session: %s)", e.Session))
 		}
@@ -55,19 +307,103 @@ func FormatJournalEntries(entries []JournalEntry) string {
 			b.WriteString(fmt.Sprintf(" [%s]", strings.Join(e.Tags, ", ")))
 		}
 		b.WriteString("\n")
+		if withContext {
+			if ctx := e.Context.String(); ctx != "" {
+				b.WriteString(fmt.Sprintf("   context: %s\n", ctx))
+			}
+		}
 	}
 	return b.String()
 }
 
+// indentInsight indents every line after the first so multi-line entries
+// stay inside their list item.
+func indentInsight(insight, indent string) string {
+	return strings.ReplaceAll(strings.TrimRight(insight, "\n"), "\n", "\n"+indent)
+}
+
+// editInsight opens $EDITOR on a temp file holding initial and returns what was saved.
+func editInsight(initial string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		return "", fmt.Errorf("--edit needs $EDITOR to be set")
+	}
+	f, err := os.CreateTemp("", "metacog-journal-*.md")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(initial)
+	f.Close()
+	if err != nil {
+		return "", fmt.Errorf("cannot write temp file: %w", err)
+	}
+
+	// Run through the shell so EDITOR may carry arguments, e.g. "code --wait"
+	c := exec.Command("sh", "-c", editor+` "$1"`, "sh", f.Name())
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("cannot read edited file: %w", err)
+	}
+	return string(data), nil
+}
+
+// readInsight takes the insight from exactly one of: the positional
+// argument, stdin (--stdin), or an editor session seeded with initial (--edit).
+func readInsight(args []string, stdin io.Reader, fromStdin, edit bool, initial string) (string, error) {
+	sources := 0
+	for _, set := range []bool{len(args) > 0, fromStdin, edit} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", fmt.Errorf("give the insight as an argument, --stdin or --edit, not several")
+	}
+
+	var text string
+	switch {
+	case len(args) > 0:
+		text = args[len(args)-1]
+	case fromStdin:
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("cannot read stdin: %w", err)
+		}
+		text = string(data)
+	case edit:
+		edited, err := editInsight(initial)
+		if err != nil {
+			return "", err
+		}
+		text = edited
+	default:
+		return "", fmt.Errorf("provide an insight to record, or use 'metacog journal list'")
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("empty insight; nothing recorded")
+	}
+	return text, nil
+}
+
 var journalTags []string
+var journalStdin bool
+var journalEdit bool
 
 var journalCmd = &cobra.Command{
 	Use:   "journal [insight]",
 	Short: "Record or review practice insights",
 	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 {
-			return fmt.Errorf("provide an insight to record, or use 'metacog journal list'")
+		insight, err := readInsight(args, cmd.InOrStdin(), journalStdin, journalEdit, "")
+		if err != nil {
+			return err
 		}
 
 		sm := DefaultStateManager()
@@ -77,19 +413,25 @@ var journalCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
 
 		entry := JournalEntry{
+			ID:        newJournalID(),
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
-			Insight:   args[0],
+			Insight:   insight,
 			Session:   s.Session,
 			Tags:      journalTags,
+			Context:   snapshotJournalContext(s, merged.History),
 		}
 
 		if err := sm.AppendJournal(entry); err != nil {
 			return err
 		}
 
-		output := fmt.Sprintf("Journal: %s", entry.Insight)
+		output := fmt.Sprintf("Journal %s: %s", entry.ID, indentInsight(entry.Insight, "  "))
 		if entry.Session != "" {
 			output += fmt.Sprintf(" (session: %s)", entry.Session)
 		}
@@ -98,36 +440,222 @@ var journalCmd = &cobra.Command{
 	},
 }
 
-var journalListTag string
+var journalAmendTags []string
+var journalAmendReason string
+
+var journalAmendCmd = &cobra.Command{
+	Use:   "amend <id> [insight]",
+	Short: "Replace an entry's text or tags; the original stays in the journal file",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		retag := cmd.Flags().Changed("tag")
+		keepText := retag && len(args) == 1 && !journalStdin && !journalEdit
+
+		var insight string
+		if !keepText {
+			entries, err := sm.LoadJournal()
+			if err != nil {
+				return err
+			}
+			target, err := findJournalEntry(entries, args[0])
+			if err != nil {
+				return err
+			}
+			if insight, err = readInsight(args[1:], cmd.InOrStdin(), journalStdin, journalEdit, target.Insight+"\n"); err != nil {
+				return err
+			}
+		}
+
+		var id string
+		err := sm.UpdateJournal(func(entries []JournalEntry) (JournalEntry, error) {
+			target, err := findJournalEntry(entries, args[0])
+			if err != nil {
+				return JournalEntry{}, err
+			}
+			if keepText {
+				insight = target.Insight
+			}
+			if insight == target.Insight && !retag {
+				return JournalEntry{}, fmt.Errorf("entry %s is unchanged; nothing amended", target.ID)
+			}
+			id = target.ID
+			record := JournalEntry{
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Insight:   insight,
+				Op:        JournalAmend,
+				Target:    target.ID,
+				Reason:    journalAmendReason,
+			}
+			if retag {
+				record.Tags = append([]string{}, journalAmendTags...)
+			}
+			return record, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		output := fmt.Sprintf("Amended %s: %s", id, indentInsight(insight, "  "))
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+var journalDeleteReason string
+
+var journalDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Hide an entry from listings; a tombstone is appended, nothing is erased",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		var deleted JournalEntry
+		err := sm.UpdateJournal(func(entries []JournalEntry) (JournalEntry, error) {
+			target, err := findJournalEntry(entries, args[0])
+			if err != nil {
+				return JournalEntry{}, err
+			}
+			deleted = *target
+			return JournalEntry{
+				Timestamp: time.Now().UTC().Format(time.RFC3339),
+				Op:        JournalDelete,
+				Target:    target.ID,
+				Reason:    journalDeleteReason,
+			}, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Deleted %s: %s", deleted.ID, indentInsight(deleted.Insight, "  ")), nil))
+		return nil
+	},
+}
+
+var journalListTags []string
+var journalListAny bool
+var journalListAll bool
 var journalListSession string
+var journalListSince string
+var journalListUntil string
+var journalListGrep string
+var journalListIdentity string
 var journalListLast int
+var journalListWithContext bool
+var journalStrict bool
+
+func loadJournalFor(sm *StateManager) ([]JournalEntry, error) {
+	if journalStrict {
+		return sm.LoadJournalStrict()
+	}
+	return sm.LoadJournal()
+}
+
+func journalListFilter() (JournalFilter, error) {
+	f := JournalFilter{
+		Tags:     journalListTags,
+		AllTags:  journalListAll,
+		Session:  journalListSession,
+		Identity: journalListIdentity,
+	}
+	if journalListAny && journalListAll {
+		return f, fmt.Errorf("use --any or --all, not both")
+	}
+	var err error
+	if journalListSince != "" {
+		if f.Since, err = parseJournalTime("since", journalListSince, false); err != nil {
+			return f, err
+		}
+	}
+	if journalListUntil != "" {
+		if f.Until, err = parseJournalTime("until", journalListUntil, true); err != nil {
+			return f, err
+		}
+	}
+	if journalListGrep != "" {
+		if f.Grep, err = regexp.Compile(journalListGrep); err != nil {
+			return f, fmt.Errorf("--grep: %w", err)
+		}
+	}
+	return f, nil
+}
 
 var journalListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List journal entries",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := journalListFilter()
+		if err != nil {
+			return err
+		}
+
 		sm := DefaultStateManager()
-		entries, err := sm.LoadJournal()
+		entries, err := loadJournalFor(sm)
 		if err != nil {
 			return err
 		}
+		if filter.Session != "" {
+			s, err := sm.Load()
+			if err != nil {
+				return err
+			}
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			filter.Renames = sessionRenames(merged.History)
+		}
 
-		entries = FilterJournal(entries, journalListTag, journalListSession)
+		entries = FilterJournal(entries, filter)
 
 		if journalListLast > 0 && len(entries) > journalListLast {
 			entries = entries[len(entries)-journalListLast:]
 		}
 
-		fmt.Println(FormatOutput(jsonOutput, FormatJournalEntries(entries), nil))
+		fmt.Println(FormatOutput(jsonOutput, FormatJournalEntries(entries, journalListWithContext), nil))
+		return nil
+	},
+}
+
+var journalTagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List every journal tag with its entry count",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := loadJournalFor(DefaultStateManager())
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, FormatJournalTags(JournalTagCounts(entries)), nil))
 		return nil
 	},
 }
 
 func init() {
 	journalCmd.Flags().StringArrayVar(&journalTags, "tag", nil, "Tag this insight (repeatable)")
-	journalListCmd.Flags().StringVar(&journalListTag, "tag", "", "Filter by tag")
+	for _, c := range []*cobra.Command{journalCmd, journalAmendCmd} {
+		c.Flags().BoolVar(&journalStdin, "stdin", false, "Read the insight from stdin")
+		c.Flags().BoolVar(&journalEdit, "edit", false, "Write the insight in $EDITOR")
+	}
+	journalAmendCmd.Flags().StringArrayVar(&journalAmendTags, "tag", nil, "Replace the entry's tags (repeatable); without new text, only the tags change")
+	journalAmendCmd.Flags().StringVar(&journalAmendReason, "reason", "", "Why the entry was amended")
+	journalDeleteCmd.Flags().StringVar(&journalDeleteReason, "reason", "", "Why the entry was deleted")
+	journalListCmd.Flags().StringArrayVar(&journalListTags, "tag", nil, "Filter by tag (repeatable)")
+	journalListCmd.Flags().BoolVar(&journalListAny, "any", false, "Match entries with any of the --tag values (default)")
+	journalListCmd.Flags().BoolVar(&journalListAll, "all", false, "Match only entries with every --tag value")
 	journalListCmd.Flags().StringVar(&journalListSession, "session", "", "Filter by session")
+	journalListCmd.Flags().StringVar(&journalListSince, "since", "", "Only entries at or after this date (YYYY-MM-DD or RFC3339)")
+	journalListCmd.Flags().StringVar(&journalListUntil, "until", "", "Only entries at or before this date (YYYY-MM-DD or RFC3339)")
+	journalListCmd.Flags().StringVar(&journalListGrep, "grep", "", "Only entries whose text matches this regular expression")
+	journalListCmd.Flags().StringVar(&journalListIdentity, "identity", "", "Only entries written while inhabiting this identity")
 	journalListCmd.Flags().IntVar(&journalListLast, "last", 0, "Show last N entries")
+	journalListCmd.Flags().BoolVar(&journalListWithContext, "with-context", false, "Show the identity, substrate, stratagem step and history position each entry was written in")
+	for _, c := range []*cobra.Command{journalListCmd, journalTagsCmd} {
+		c.Flags().BoolVar(&journalStrict, "strict", false, "Fail with the line numbers of malformed journal lines instead of skipping them")
+	}
 	journalCmd.AddCommand(journalListCmd)
+	journalCmd.AddCommand(journalTagsCmd)
+	journalCmd.AddCommand(journalAmendCmd)
+	journalCmd.AddCommand(journalDeleteCmd)
 	rootCmd.AddCommand(journalCmd)
 }
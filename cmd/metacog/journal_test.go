@@ -3,8 +3,10 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRecordJournal(t *testing.T) {
@@ -95,7 +97,7 @@ func TestFilterByTag(t *testing.T) {
 		{Insight: "c", Tags: []string{"practice", "identity"}},
 	}
 
-	filtered := FilterJournal(entries, "practice", "")
+	filtered := FilterJournal(entries, JournalFilter{Tags: []string{"practice"}})
 	if len(filtered) != 2 {
 		t.Errorf("expected 2 entries with tag=practice, got %d", len(filtered))
 	}
@@ -113,19 +115,41 @@ func TestFilterBySession(t *testing.T) {
 		{Insight: "c", Session: "s1"},
 	}
 
-	filtered := FilterJournal(entries, "", "s1")
+	filtered := FilterJournal(entries, JournalFilter{Session: "s1"})
 	if len(filtered) != 2 {
 		t.Errorf("expected 2 entries with session=s1, got %d", len(filtered))
 	}
 }
 
+func TestFilterBySessionFollowsRenames(t *testing.T) {
+	history := []HistoryEntry{
+		{Action: "session", Timestamp: "2025-01-01T00:00:00Z", Params: map[string]string{"name": "draft", "event": "started"}},
+		{Action: "session", Timestamp: "2025-01-02T00:00:00Z", Params: map[string]string{"name": "draft", "event": "renamed", "to": "api"}},
+	}
+	entries := []JournalEntry{
+		{ID: "before", Timestamp: "2025-01-01T12:00:00Z", Insight: "a", Session: "draft"},
+		{ID: "after", Timestamp: "2025-01-03T00:00:00Z", Insight: "b", Session: "api"},
+		// A later session that reused the old name is a different session
+		{ID: "reused", Timestamp: "2025-01-04T00:00:00Z", Insight: "c", Session: "draft"},
+	}
+
+	got := FilterJournal(entries, JournalFilter{Session: "api", Renames: sessionRenames(history)})
+	if len(got) != 2 || got[0].ID != "before" || got[1].ID != "after" {
+		t.Errorf("expected entries from before and after the rename, got %+v", got)
+	}
+	got = FilterJournal(entries, JournalFilter{Session: "draft", Renames: sessionRenames(history)})
+	if len(got) != 1 || got[0].ID != "reused" {
+		t.Errorf("the old name should only match entries written after the rename, got %+v", got)
+	}
+}
+
 func TestFormatJournalEntries(t *testing.T) {
 	entries := []JournalEntry{
 		{Timestamp: "2025-01-01T00:00:00Z", Insight: "first insight", Tags: []string{"practice"}},
 		{Timestamp: "2025-01-02T00:00:00Z", Insight: "second insight", Session: "deep-dive"},
 	}
 
-	output := FormatJournalEntries(entries)
+	output := FormatJournalEntries(entries, false)
 	if !strings.Contains(output, "first insight") {
 		t.Error("should contain first insight")
 	}
@@ -138,7 +162,7 @@ func TestFormatJournalEntries(t *testing.T) {
 }
 
 func TestFormatJournalEntriesEmpty(t *testing.T) {
-	output := FormatJournalEntries(nil)
+	output := FormatJournalEntries(nil, false)
 	if output != "No journal entries." {
 		t.Errorf("expected empty message, got %s", output)
 	}
@@ -213,3 +237,259 @@ func TestFormatRecentInsightsEmpty(t *testing.T) {
 		t.Errorf("expected empty string, got %q", output)
 	}
 }
+
+func TestSnapshotJournalContext(t *testing.T) {
+	s := NewState()
+	if ctx := snapshotJournalContext(s, s.History); ctx != nil {
+		t.Errorf("expected no context for a fresh state, got %+v", ctx)
+	}
+
+	applyBecome(s, "Feynman", "playful physics", "Caltech")
+	applyDrugs(s, "caffeine", "espresso", "sharp")
+	s.Stratagem = &ActiveStratagem{Name: "pivot", Step: 1, RunID: "abcd1234"}
+	archived := []HistoryEntry{{Action: "become"}, {Action: "feel"}}
+
+	ctx := snapshotJournalContext(s, append(archived, s.History...))
+	if ctx == nil {
+		t.Fatal("expected context")
+	}
+	if ctx.Identity == nil || ctx.Identity.Name != "Feynman" {
+		t.Errorf("expected identity Feynman, got %+v", ctx.Identity)
+	}
+	if ctx.Substrate == nil || ctx.Substrate.Substance != "caffeine" {
+		t.Errorf("expected substrate caffeine, got %+v", ctx.Substrate)
+	}
+	if ctx.Stratagem != "pivot" || ctx.StratagemStep != 2 || ctx.StratagemRun != "abcd1234" {
+		t.Errorf("expected pivot step 2 run abcd1234, got %s %d %s", ctx.Stratagem, ctx.StratagemStep, ctx.StratagemRun)
+	}
+	if ctx.HistoryIndex != 4 || ctx.HistoryAction != "drugs" {
+		t.Errorf("expected history #4 drugs, got #%d %s", ctx.HistoryIndex, ctx.HistoryAction)
+	}
+
+	want := "as Feynman, on caffeine, THE PIVOT step 2/5, after #4 drugs"
+	if got := ctx.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFormatJournalEntriesWithContext(t *testing.T) {
+	entries := []JournalEntry{
+		{Timestamp: "2025-01-01T00:00:00Z", Insight: "plain"},
+		{Timestamp: "2025-01-02T00:00:00Z", Insight: "situated", Context: &JournalContext{
+			Identity: &Identity{Name: "Borges"}, HistoryIndex: 7, HistoryAction: "become",
+		}},
+	}
+
+	if output := FormatJournalEntries(entries, false); strings.Contains(output, "context:") {
+		t.Error("context should be hidden without --with-context")
+	}
+	output := FormatJournalEntries(entries, true)
+	if !strings.Contains(output, "context: as Borges, after #7 become") {
+		t.Errorf("expected context line, got %s", output)
+	}
+	if strings.Count(output, "context:") != 1 {
+		t.Errorf("entries without context should not get a context line, got %s", output)
+	}
+
+	if insights := FormatRecentInsights(entries, 5); !strings.Contains(insights, "situated — as Borges") {
+		t.Errorf("recent insights should show context, got %s", insights)
+	}
+}
+
+func TestLoadJournalFoldsAmendAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+
+	records := []JournalEntry{
+		{ID: "aaaa0001", Timestamp: "2025-01-01T00:00:00Z", Insight: "frist draft", Tags: []string{"draft"}},
+		{ID: "aaaa0002", Timestamp: "2025-01-02T00:00:00Z", Insight: "regrettable"},
+		{Timestamp: "2025-01-03T00:00:00Z", Op: JournalAmend, Target: "aaaa0001", Insight: "first draft", Reason: "typo"},
+		{Timestamp: "2025-01-04T00:00:00Z", Op: JournalDelete, Target: "aaaa0002"},
+		{Timestamp: "2025-01-05T00:00:00Z", Op: JournalAmend, Target: "aaaa0002", Insight: "too late"},
+		{Timestamp: "2025-01-06T00:00:00Z", Op: JournalDelete, Target: "missing"},
+	}
+	for _, r := range records {
+		if err := sm.AppendJournal(r); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	entries, err := sm.LoadJournal()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry after folding, got %d: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Insight != "first draft" || e.AmendedAt != "2025-01-03T00:00:00Z" {
+		t.Errorf("expected amended text and timestamp, got %q at %q", e.Insight, e.AmendedAt)
+	}
+	if len(e.Tags) != 1 || e.Tags[0] != "draft" {
+		t.Errorf("amend without tags should keep tags, got %v", e.Tags)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "journal.jsonl"))
+	if !strings.Contains(string(data), "frist draft") || !strings.Contains(string(data), "regrettable") {
+		t.Error("journal file should keep original and deleted text for auditing")
+	}
+}
+
+func TestLegacyJournalIDStable(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+	os.WriteFile(filepath.Join(dir, "journal.jsonl"), []byte(
+		`{"timestamp":"2025-01-01T00:00:00Z","insight":"old one"}`+"\n"+
+			`{"timestamp":"2025-01-01T00:00:00Z","insight":"old two"}`+"\n"), 0644)
+
+	first, _ := sm.LoadJournal()
+	second, _ := sm.LoadJournal()
+	if len(first) != 2 || first[0].ID == "" || first[0].ID == first[1].ID {
+		t.Fatalf("expected distinct IDs for legacy entries, got %+v", first)
+	}
+	if first[0].ID != second[0].ID {
+		t.Errorf("legacy ID changed between loads: %s vs %s", first[0].ID, second[0].ID)
+	}
+
+	sm.AppendJournal(JournalEntry{Timestamp: "2025-01-02T00:00:00Z", Op: JournalDelete, Target: first[0].ID})
+	entries, _ := sm.LoadJournal()
+	if len(entries) != 1 || entries[0].Insight != "old two" {
+		t.Errorf("expected legacy entry to be deletable by derived ID, got %+v", entries)
+	}
+}
+
+func TestReadInsight(t *testing.T) {
+	text, err := readInsight(nil, strings.NewReader("line one\nline two\n\n"), true, false, "")
+	if err != nil {
+		t.Fatalf("stdin: %v", err)
+	}
+	if text != "line one\nline two" {
+		t.Errorf("expected trimmed multi-line text, got %q", text)
+	}
+
+	if _, err := readInsight([]string{"arg"}, strings.NewReader("x"), true, false, ""); err == nil {
+		t.Error("expected error when both an argument and --stdin are given")
+	}
+	if _, err := readInsight(nil, strings.NewReader("  \n"), true, false, ""); err == nil {
+		t.Error("expected error for an empty insight")
+	}
+	if _, err := readInsight(nil, nil, false, false, ""); err == nil {
+		t.Error("expected error with no insight source")
+	}
+}
+
+func TestFormatJournalEntriesMultiLine(t *testing.T) {
+	entries := []JournalEntry{{ID: "abcd1234", Timestamp: "2025-01-01T00:00:00Z", Insight: "para one\npara two", AmendedAt: "2025-01-02T00:00:00Z"}}
+	output := FormatJournalEntries(entries, false)
+	if !strings.Contains(output, "1. abcd1234 [2025-01-01T00:00:00Z] para one\n   para two") {
+		t.Errorf("expected ID and indented continuation, got %q", output)
+	}
+	if !strings.Contains(output, "(amended 2025-01-02T00:00:00Z)") {
+		t.Errorf("expected amended marker, got %q", output)
+	}
+}
+
+func TestFilterJournalQuery(t *testing.T) {
+	entries := []JournalEntry{
+		{ID: "1", Timestamp: "2025-01-01T09:00:00Z", Insight: "Pivot found a blind spot", Tags: []string{"pivot", "identity"},
+			Context: &JournalContext{Identity: &Identity{Name: "Feynman"}}},
+		{ID: "2", Timestamp: "2025-01-02T23:30:00Z", Insight: "substrate mattered more", Tags: []string{"substrate"}},
+		{ID: "3", Timestamp: "2025-01-03T10:00:00Z", Insight: "pivot again", Tags: []string{"pivot"}},
+	}
+	ids := func(es []JournalEntry) string {
+		var out []string
+		for _, e := range es {
+			out = append(out, e.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	since, _ := parseJournalTime("since", "2025-01-02", false)
+	until, _ := parseJournalTime("until", "2025-01-02", true)
+	cases := []struct {
+		name   string
+		filter JournalFilter
+		want   string
+	}{
+		{"any tag", JournalFilter{Tags: []string{"identity", "substrate"}}, "1,2"},
+		{"all tags", JournalFilter{Tags: []string{"pivot", "identity"}, AllTags: true}, "1"},
+		{"date range", JournalFilter{Since: since, Until: until}, "2"},
+		{"since only", JournalFilter{Since: since}, "2,3"},
+		{"grep", JournalFilter{Grep: regexp.MustCompile(`(?i)^pivot`)}, "1,3"},
+		{"identity", JournalFilter{Identity: "feynman"}, "1"},
+		{"combined", JournalFilter{Tags: []string{"pivot"}, Since: since}, "3"},
+	}
+	for _, c := range cases {
+		if got := ids(FilterJournal(entries, c.filter)); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+func TestParseJournalTime(t *testing.T) {
+	if _, err := parseJournalTime("since", "last tuesday", false); err == nil {
+		t.Error("expected error for unparseable date")
+	}
+	ts, err := parseJournalTime("since", "2025-01-02T03:04:05Z", true)
+	if err != nil || ts.Format(time.RFC3339) != "2025-01-02T03:04:05Z" {
+		t.Errorf("RFC3339 should be taken verbatim, got %v (%v)", ts, err)
+	}
+}
+
+func TestJournalTagCounts(t *testing.T) {
+	entries := []JournalEntry{
+		{Tags: []string{"pivot", "identity"}},
+		{Tags: []string{"pivot"}},
+		{Tags: []string{"abc"}},
+	}
+	output := FormatJournalTags(JournalTagCounts(entries))
+	want := "   2  pivot\n   1  abc\n   1  identity"
+	if output != want {
+		t.Errorf("expected %q, got %q", want, output)
+	}
+	if FormatJournalTags(nil) != "No tagged journal entries." {
+		t.Error("expected empty message")
+	}
+}
+
+func TestLoadJournalStrict(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+	os.WriteFile(filepath.Join(dir, "journal.jsonl"), []byte(
+		`{"timestamp":"2025-01-01T00:00:00Z","insight":"good"}`+"\n"+
+			"\n"+
+			"not json\n"+
+			`{"timestamp":"2025-01-02T00:00:00Z","insight":"also good"}`+"\n"+
+			`{"timestamp":`+"\n"), 0644)
+
+	if entries, err := sm.LoadJournal(); err != nil || len(entries) != 2 {
+		t.Errorf("lenient load should skip malformed lines, got %d (%v)", len(entries), err)
+	}
+	_, err := sm.LoadJournalStrict()
+	if err == nil {
+		t.Fatal("expected strict load to fail")
+	}
+	if !strings.Contains(err.Error(), "2 malformed lines: 3, 5") {
+		t.Errorf("expected line numbers 3 and 5, got %v", err)
+	}
+}
+
+func TestUpdateJournalChecksCurrentJournal(t *testing.T) {
+	sm := NewStateManager(t.TempDir())
+	sm.AppendJournal(JournalEntry{ID: "a1", Insight: "one"})
+	sm.AppendJournal(JournalEntry{Op: JournalDelete, Target: "a1"})
+
+	err := sm.UpdateJournal(func(entries []JournalEntry) (JournalEntry, error) {
+		if _, err := findJournalEntry(entries, "a1"); err != nil {
+			return JournalEntry{}, err
+		}
+		return JournalEntry{Op: JournalAmend, Target: "a1", Insight: "two"}, nil
+	})
+	if err == nil {
+		t.Error("an entry deleted before the update should not be found")
+	}
+	data, _ := os.ReadFile(filepath.Join(sm.dir, "journal.jsonl"))
+	if strings.Count(string(data), "\n") != 2 {
+		t.Errorf("a failed update should append nothing:\n%s", data)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// MaxLayerDepth bounds the identity and substrate stacks. Pushing past it drops the oldest layer.
+const MaxLayerDepth = 7
+
+// Identities returns the identity stack, bottom first. States written before
+// stacks existed only carry Identity, which is treated as a single layer.
+func (s *State) Identities() []Identity {
+	if len(s.IdentityStack) == 0 && s.Identity != nil {
+		return []Identity{*s.Identity}
+	}
+	return s.IdentityStack
+}
+
+// Substrates returns the substrate stack, bottom first, with the same legacy fallback as Identities.
+func (s *State) Substrates() []Substrate {
+	if len(s.SubstrateStack) == 0 && s.Substrate != nil {
+		return []Substrate{*s.Substrate}
+	}
+	return s.SubstrateStack
+}
+
+// PushIdentity layers id on top of the stack. An existing layer with the same
+// name is lifted to the top rather than duplicated.
+func (s *State) PushIdentity(id Identity) {
+	var stack []Identity
+	for _, existing := range s.Identities() {
+		if existing.Name != id.Name {
+			stack = append(stack, existing)
+		}
+	}
+	stack = append(stack, id)
+	if len(stack) > MaxLayerDepth {
+		stack = stack[len(stack)-MaxLayerDepth:]
+	}
+	s.IdentityStack = stack
+	s.syncLayers()
+}
+
+// PushSubstrate layers sub on top of the stack, lifting an existing layer with the same substance.
+func (s *State) PushSubstrate(sub Substrate) {
+	var stack []Substrate
+	for _, existing := range s.Substrates() {
+		if existing.Substance != sub.Substance {
+			stack = append(stack, existing)
+		}
+	}
+	stack = append(stack, sub)
+	if len(stack) > MaxLayerDepth {
+		stack = stack[len(stack)-MaxLayerDepth:]
+	}
+	s.SubstrateStack = stack
+	s.syncLayers()
+}
+
+// ReleaseIdentity pops the top identity, or drops the named one from anywhere in the stack.
+func (s *State) ReleaseIdentity(name string) (Identity, error) {
+	stack := s.Identities()
+	if len(stack) == 0 {
+		return Identity{}, fmt.Errorf("no identity layers to release")
+	}
+	idx := len(stack) - 1
+	if name != "" {
+		idx = -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].Name == name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return Identity{}, fmt.Errorf("no identity layer named %q", name)
+		}
+	}
+	released := stack[idx]
+	s.IdentityStack = append(append([]Identity{}, stack[:idx]...), stack[idx+1:]...)
+	s.syncLayers()
+	return released, nil
+}
+
+// ReleaseSubstrate pops the top substrate, or drops the one with the named substance.
+func (s *State) ReleaseSubstrate(substance string) (Substrate, error) {
+	stack := s.Substrates()
+	if len(stack) == 0 {
+		return Substrate{}, fmt.Errorf("no substrate layers to release")
+	}
+	idx := len(stack) - 1
+	if substance != "" {
+		idx = -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].Substance == substance {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return Substrate{}, fmt.Errorf("no substrate layer with substance %q", substance)
+		}
+	}
+	released := stack[idx]
+	s.SubstrateStack = append(append([]Substrate{}, stack[:idx]...), stack[idx+1:]...)
+	s.syncLayers()
+	return released, nil
+}
+
+// ClearLayers drops every identity and substrate layer.
+func (s *State) ClearLayers() {
+	s.IdentityStack = nil
+	s.SubstrateStack = nil
+	s.syncLayers()
+}
+
+func (s *State) syncLayers() {
+	s.Identity = nil
+	if n := len(s.IdentityStack); n > 0 {
+		top := s.IdentityStack[n-1]
+		s.Identity = &top
+	}
+	s.Substrate = nil
+	if n := len(s.SubstrateStack); n > 0 {
+		top := s.SubstrateStack[n-1]
+		s.Substrate = &top
+	}
+}
+
+// Release drops a layer or closes a constraint. With no name it pops the top
+// identity (or substrate when substrateOnly is set). A name is matched against
+// active constraint IDs and kinds first, then identity names, then substances.
+func Release(s *State, name string, substrateOnly bool, reason string) (string, error) {
+	if name != "" && !substrateOnly {
+		if _, ok := FindConstraint(s, name); ok {
+			c, err := CloseConstraint(s, name, "release", reason)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Released %s constraint %s: %s", c.Kind, c.ID, c.Summary), nil
+		}
+	}
+
+	params := func(layer, released string) map[string]string {
+		p := map[string]string{"layer": layer, "name": released}
+		if reason != "" {
+			p["reason"] = reason
+		}
+		return p
+	}
+
+	if !substrateOnly {
+		id, err := s.ReleaseIdentity(name)
+		if err == nil {
+			s.AddHistory(HistoryEntry{
+				Action: "release",
+				Params: params("identity", id.Name),
+			})
+			return fmt.Sprintf("Released identity: %s. %d identity layers remain.", id.Name, len(s.Identities())), nil
+		}
+		if name == "" {
+			return "", err
+		}
+	}
+
+	sub, err := s.ReleaseSubstrate(name)
+	if err != nil {
+		if name != "" && !substrateOnly {
+			return "", fmt.Errorf("nothing active named %q. Run 'metacog status' to see active layers and constraints", name)
+		}
+		return "", err
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "release",
+		Params: params("substrate", sub.Substance),
+	})
+	return fmt.Sprintf("Released substrate: %s. %d substrate layers remain.", sub.Substance, len(s.Substrates())), nil
+}
+
+var releaseSubstrate bool
+var releaseReason string
+
+var releaseCmd = &cobra.Command{
+	Use:   "release [name]",
+	Short: "Pop the top identity layer, or drop a named identity, substrate, or constraint",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) == 1 {
+			name = args[0]
+		}
+		sm := DefaultStateManager()
+		var output string
+		err := sm.SaveWithLock(func(s *State) error {
+			var err error
+			output, err = Release(s, name, releaseSubstrate, releaseReason)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+func init() {
+	releaseCmd.Flags().BoolVar(&releaseSubstrate, "substrate", false, "Release from the substrate stack instead of the identity stack")
+	releaseCmd.Flags().StringVar(&releaseReason, "reason", "", "Why the layer or constraint is being released (recorded in history)")
+	rootCmd.AddCommand(releaseCmd)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBecomePushesIdentityLayers(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Sun Ra", "cosmic jazz", "Arkestra rehearsal")
+	applyBecome(s, "Octavia Butler", "parable", "Pasadena notebook")
+	applyBecome(s, "Lynn Margulis", "endosymbiosis", "microbial mat")
+
+	stack := s.Identities()
+	if len(stack) != 3 {
+		t.Fatalf("expected 3 identity layers, got %d", len(stack))
+	}
+	if s.Identity == nil || s.Identity.Name != "Lynn Margulis" {
+		t.Errorf("Identity should mirror the top layer, got %+v", s.Identity)
+	}
+	if stack[0].Name != "Sun Ra" {
+		t.Errorf("expected Sun Ra at the bottom, got %s", stack[0].Name)
+	}
+}
+
+func TestPushIdentityLiftsExistingName(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Ada", "verification", "lab")
+	applyBecome(s, "Eno", "generative", "studio")
+	applyBecome(s, "Ada", "poetical science", "salon")
+
+	stack := s.Identities()
+	if len(stack) != 2 {
+		t.Fatalf("expected 2 layers after re-becoming Ada, got %d", len(stack))
+	}
+	if stack[1].Name != "Ada" || stack[1].Lens != "poetical science" {
+		t.Errorf("expected updated Ada on top, got %+v", stack[1])
+	}
+}
+
+func TestPushIdentityCapsDepth(t *testing.T) {
+	s := NewState()
+	for i := 0; i < MaxLayerDepth+3; i++ {
+		s.PushIdentity(Identity{Name: string(rune('A' + i))})
+	}
+	if len(s.Identities()) != MaxLayerDepth {
+		t.Errorf("expected stack capped at %d, got %d", MaxLayerDepth, len(s.Identities()))
+	}
+	if s.Identities()[0].Name != "D" {
+		t.Errorf("expected oldest layers dropped, bottom is %s", s.Identities()[0].Name)
+	}
+}
+
+func TestLegacyIdentityTreatedAsSingleLayer(t *testing.T) {
+	s := NewState()
+	s.Identity = &Identity{Name: "Ada", Lens: "verification", Env: "lab"}
+	if len(s.Identities()) != 1 {
+		t.Fatalf("expected legacy identity as one layer, got %d", len(s.Identities()))
+	}
+	applyBecome(s, "Eno", "generative", "studio")
+	if len(s.Identities()) != 2 {
+		t.Errorf("expected legacy identity preserved beneath new layer, got %d", len(s.Identities()))
+	}
+}
+
+func TestReleasePopsTopIdentity(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Ada", "verification", "lab")
+	applyBecome(s, "Eno", "generative", "studio")
+
+	out, err := Release(s, "", false, "")
+	if err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if !strings.Contains(out, "Eno") {
+		t.Errorf("expected Eno released, got %s", out)
+	}
+	if s.Identity == nil || s.Identity.Name != "Ada" {
+		t.Errorf("expected Ada on top after pop, got %+v", s.Identity)
+	}
+	last := s.History[len(s.History)-1]
+	if last.Action != "release" || last.Params["layer"] != "identity" || last.Params["name"] != "Eno" {
+		t.Errorf("expected release history entry, got %+v", last)
+	}
+}
+
+func TestReleaseByNameDropsMiddleLayer(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Ada", "verification", "lab")
+	applyBecome(s, "Eno", "generative", "studio")
+	applyBecome(s, "Cage", "chance", "silence")
+
+	if _, err := Release(s, "Eno", false, ""); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	stack := s.Identities()
+	if len(stack) != 2 || stack[0].Name != "Ada" || stack[1].Name != "Cage" {
+		t.Errorf("expected [Ada Cage], got %+v", stack)
+	}
+}
+
+func TestReleaseFallsBackToSubstrate(t *testing.T) {
+	s := NewState()
+	applyDrugs(s, "caffeine", "antagonism", "sharp")
+	applyDrugs(s, "psilocybin", "5-HT2A", "porous")
+
+	if _, err := Release(s, "caffeine", false, ""); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if len(s.Substrates()) != 1 || s.Substrate.Substance != "psilocybin" {
+		t.Errorf("expected only psilocybin left, got %+v", s.Substrates())
+	}
+}
+
+func TestReleaseSubstrateOnly(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Ada", "verification", "lab")
+	applyDrugs(s, "caffeine", "antagonism", "sharp")
+
+	if _, err := Release(s, "", true, ""); err != nil {
+		t.Fatalf("release failed: %v", err)
+	}
+	if s.Substrate != nil {
+		t.Error("expected substrate cleared")
+	}
+	if s.Identity == nil {
+		t.Error("identity should be untouched")
+	}
+}
+
+func TestReleaseUnknownName(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Ada", "verification", "lab")
+	if _, err := Release(s, "nobody", false, ""); err == nil {
+		t.Error("expected error releasing unknown name")
+	}
+}
+
+func TestReleaseEmptyStack(t *testing.T) {
+	s := NewState()
+	if _, err := Release(s, "", false, ""); err == nil {
+		t.Error("expected error releasing from empty stack")
+	}
+}
+
+func TestFormatStatusShowsAllLayers(t *testing.T) {
+	s := NewState()
+	applyBecome(s, "Sun Ra", "cosmic jazz", "Arkestra rehearsal")
+	applyBecome(s, "Octavia Butler", "parable", "Pasadena notebook")
+	applyDrugs(s, "caffeine", "antagonism", "sharp")
+
+	out := FormatStatus(s)
+	for _, want := range []string{"Identity stack (2 layers, top first)", "Sun Ra", "Octavia Butler", "Substrate: caffeine"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("status missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Octavia Butler") > strings.Index(out, "Sun Ra") {
+		t.Error("top layer should be listed first")
+	}
+}
+
+func TestLayersRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+	s := NewState()
+	applyBecome(s, "Ada", "verification", "lab")
+	applyBecome(s, "Eno", "generative", "studio")
+	sm.Save(s)
+
+	loaded, err := sm.Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded.Identities()) != 2 || loaded.Identity.Name != "Eno" {
+		t.Errorf("expected 2 layers with Eno on top, got %+v", loaded.Identities())
+	}
+}
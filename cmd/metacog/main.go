@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +14,9 @@ var StateSchemaVersion = 1
 var rootCmd = &cobra.Command{
 	Use:   "metacog",
 	Short: "Metacognitive compositional engine",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		registerUserStratagems(metacogHome())
+	},
 }
 
 var jsonOutput bool
@@ -21,7 +25,7 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
 	Run: func(cmd *cobra.Command, args []string) {
-		output := fmt.Sprintf("metacog v%s\nstate schema: v%d\nprimitives: feel drugs become name ritual meditate counterfactual synthesis fork register chord silence excerpt commitment disjunction glossolalia\nstratagems: pivot mirror stack anchor reset invocation veil scrying sacrifice fool inversion gift zen manifold chorus trinity antinomy envoy counterpoint envoy-extreme", Version, StateSchemaVersion)
+		output := fmt.Sprintf("metacog v%s\nstate schema: v%d\nprimitives: feel drugs become name ritual meditate counterfactual synthesis fork register chord silence excerpt commitment disjunction glossolalia\nstratagems: %s", Version, StateSchemaVersion, strings.Join(allStratagemNames(), " "))
 		fmt.Println(FormatOutput(jsonOutput, output, nil))
 	},
 }
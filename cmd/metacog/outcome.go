@@ -2,76 +2,42 @@ package main
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
-func findLastCompletedStratagem(s *State) (string, int) {
-	for i := len(s.History) - 1; i >= 0; i-- {
-		h := s.History[i]
-		if h.Action == "stratagem" && h.Params["event"] == "completed" {
-			return h.Params["name"], i
-		}
-	}
-	return "", -1
+// primitiveActions are the history actions that count as practice, as opposed
+// to bookkeeping like session, stratagem or outcome events.
+var primitiveActions = map[string]bool{
+	"feel": true, "become": true, "drugs": true, "name": true, "ritual": true, "meditate": true,
+	"counterfactual": true, "synthesis": true, "fork": true, "register": true, "chord": true,
+	"silence": true, "excerpt": true, "commitment": true, "disjunction": true, "glossolalia": true,
 }
 
-func hasOutcomeAfter(s *State, afterIdx int) bool {
-	for i := afterIdx + 1; i < len(s.History); i++ {
-		if s.History[i].Action == "outcome" {
-			return true
-		}
-	}
-	return false
-}
-
-// findLastPrimitive scans backward for the last primitive entry
-// that isn't inside a stratagem span and doesn't already have an outcome after it.
-func findLastPrimitive(s *State) int {
-	for i := len(s.History) - 1; i >= 0; i-- {
-		h := s.History[i]
-		switch h.Action {
-		case "feel", "become", "drugs", "name", "ritual", "meditate", "counterfactual", "synthesis", "fork",
-			"register", "chord", "silence", "excerpt", "commitment", "disjunction", "glossolalia":
-			// Check it's not covered by a stratagem span
-			if isInsideStratagemSpan(s, i) {
-				continue
-			}
-			// Check no outcome already covers it
-			if hasOutcomeAfter(s, i) {
-				continue
-			}
-			return i
-		}
-	}
-	return -1
-}
-
-// isInsideStratagemSpan checks if index i falls between a stratagem started
-// and its completed/abandoned/aborted event.
-func isInsideStratagemSpan(s *State, idx int) bool {
-	// Scan backward from idx for the nearest stratagem boundary
-	for i := idx - 1; i >= 0; i-- {
-		h := s.History[i]
-		if h.Action == "stratagem" {
-			event := h.Params["event"]
-			if event == "started" {
-				// We're inside an active stratagem span
-				return true
-			}
-			if event == "completed" || h.Status == "abandoned" || h.Status == "aborted" {
-				// The stratagem ended before our index
-				return false
-			}
-		}
+func validateOutcomeResult(result string) error {
+	if result != "productive" && result != "unproductive" {
+		return fmt.Errorf("result must be 'productive' or 'unproductive', got %q", result)
 	}
-	return false
+	return nil
 }
 
-func recordOutcomeEntry(s *State, result, shift, stratagemName string) {
+// recordOutcomeEntry appends an outcome for t, naming it by the 1-based
+// history index of its last entry.
+func recordOutcomeEntry(s *State, result, shift string, t *OutcomeTarget) {
 	params := map[string]string{
 		"result":    result,
-		"stratagem": stratagemName,
+		"stratagem": t.Name,
+		"target":    strconv.Itoa(t.End + 1),
+	}
+	if t.Run != "" {
+		params["run"] = t.Run
+	}
+	if t.Kind == TargetStratagem && t.Status != "completed" {
+		params["status"] = t.Status
+		params["step_at"] = strconv.Itoa(t.StepAt + 1)
 	}
 	if shift != "" {
 		params["shift"] = shift
@@ -83,55 +49,215 @@ func recordOutcomeEntry(s *State, result, shift, stratagemName string) {
 }
 
 func RecordOutcome(s *State, result, shift string) error {
-	if result != "productive" && result != "unproductive" {
-		return fmt.Errorf("result must be 'productive' or 'unproductive', got %q", result)
-	}
+	_, err := RecordOutcomeFor(s, s.History, result, shift, "")
+	return err
+}
 
-	// Tier 1: completed stratagem without an outcome
-	name, idx := findLastCompletedStratagem(s)
-	if idx >= 0 && !hasOutcomeAfter(s, idx) {
-		recordOutcomeEntry(s, result, shift, name)
-		return nil
+// RecordOutcomeFor records an outcome against the target named by ref (see
+// ResolveOutcomeTarget), or the default heuristic when ref is empty. history
+// is the full history ending with s.History, so indices match 'history --full'.
+func RecordOutcomeFor(s *State, history []HistoryEntry, result, shift, ref string) (*OutcomeTarget, error) {
+	if err := validateOutcomeResult(result); err != nil {
+		return nil, err
 	}
-
-	// Tier 2: freestyle primitives without an outcome
-	pidx := findLastPrimitive(s)
-	if pidx >= 0 {
-		recordOutcomeEntry(s, result, shift, "freestyle")
-		return nil
+	t, err := ResolveOutcomeTarget(OutcomeTargets(history), ref)
+	if err != nil {
+		return nil, err
 	}
-
-	// If tier 1 found a stratagem but it already had an outcome
-	if idx >= 0 {
-		return fmt.Errorf("outcome already recorded for this stratagem. Use --amend to update")
+	if t.Outcome >= 0 {
+		return nil, fmt.Errorf("outcome already recorded for %s (#%d). Run 'metacog outcome amend %d' to revise it", t, t.Outcome+1, t.Outcome+1)
 	}
+	recordOutcomeEntry(s, result, shift, t)
+	return t, nil
+}
 
-	return fmt.Errorf("no completed stratagem or freestyle primitives found in history")
+// amendOutcomeEntry sets an outcome's result (when non-empty) and shift (when
+// setShift), recording each change as an Amendment. It reports whether
+// anything changed.
+func amendOutcomeEntry(h *HistoryEntry, result, shift string, setShift bool, reason string) bool {
+	if h.Params == nil {
+		h.Params = map[string]string{}
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	changed := false
+	set := func(field, value string) {
+		if h.Params[field] == value {
+			return
+		}
+		h.Amendments = append(h.Amendments, Amendment{Timestamp: now, Field: field, From: h.Params[field], To: value, Reason: reason})
+		if value == "" {
+			delete(h.Params, field)
+		} else {
+			h.Params[field] = value
+		}
+		changed = true
+	}
+	if result != "" {
+		set("result", result)
+	}
+	if setShift {
+		set("shift", shift)
+	}
+	return changed
 }
 
+// AmendOutcome updates the most recent outcome. An empty shift clears it.
 func AmendOutcome(s *State, result, shift string) error {
-	if result != "productive" && result != "unproductive" {
-		return fmt.Errorf("result must be 'productive' or 'unproductive', got %q", result)
+	if err := validateOutcomeResult(result); err != nil {
+		return err
 	}
 
-	// Find most recent outcome
 	for i := len(s.History) - 1; i >= 0; i-- {
 		if s.History[i].Action == "outcome" {
-			s.History[i].Params["result"] = result
-			if shift != "" {
-				s.History[i].Params["shift"] = shift
-			} else {
-				delete(s.History[i].Params, "shift")
-			}
+			amendOutcomeEntry(&s.History[i], result, shift, true, "")
 			return nil
 		}
 	}
 	return fmt.Errorf("no outcome to amend")
 }
 
+// AmendOutcomeAt revises the outcome with ID ref ("12" or "#12", its 1-based
+// index in history, the full history ending with s.History). The history
+// archive is append-only, so an archived outcome is revised by an "amendment"
+// entry in live history, which mergeArchivedHistory folds back into it.
+func AmendOutcomeAt(s *State, history []HistoryEntry, ref, result, shift string, setShift bool, reason string) (RecordedOutcome, error) {
+	if result == "" && !setShift {
+		return RecordedOutcome{}, fmt.Errorf("nothing to amend: pass --result and/or --shift")
+	}
+	if result != "" {
+		if err := validateOutcomeResult(result); err != nil {
+			return RecordedOutcome{}, err
+		}
+	}
+	ref = strings.TrimPrefix(ref, "#")
+	id, err := strconv.Atoi(ref)
+	if err != nil || id < 1 || id > len(history) || history[id-1].Action != "outcome" {
+		return RecordedOutcome{}, fmt.Errorf("no outcome #%s. Run 'metacog outcome list' to see IDs", ref)
+	}
+	live := id - 1 - (len(history) - len(s.History))
+	if live >= 0 {
+		if !amendOutcomeEntry(&s.History[live], result, shift, setShift, reason) {
+			return RecordedOutcome{}, fmt.Errorf("outcome #%d already says that; nothing to amend", id)
+		}
+		return RecordedOutcome{ID: id, Entry: s.History[live]}, nil
+	}
+
+	h := cloneOutcomeEntry(history[id-1])
+	before := len(h.Amendments)
+	if !amendOutcomeEntry(&h, result, shift, setShift, reason) {
+		return RecordedOutcome{}, fmt.Errorf("outcome #%d already says that; nothing to amend", id)
+	}
+	s.AddHistory(HistoryEntry{
+		Action:     "amendment",
+		Params:     map[string]string{"outcome": strconv.Itoa(id)},
+		Amendments: h.Amendments[before:],
+	})
+	history[id-1] = h
+	return RecordedOutcome{ID: id, Entry: h}, nil
+}
+
+// cloneOutcomeEntry copies h so amending it leaves the original untouched.
+func cloneOutcomeEntry(h HistoryEntry) HistoryEntry {
+	params := make(map[string]string, len(h.Params))
+	for k, v := range h.Params {
+		params[k] = v
+	}
+	h.Params = params
+	h.Amendments = append([]Amendment(nil), h.Amendments...)
+	return h
+}
+
+// applyOutcomeAmendments folds each "amendment" entry into the outcome it
+// names, in place, so history reads with every outcome's current verdict.
+func applyOutcomeAmendments(history []HistoryEntry) {
+	for _, h := range history {
+		if h.Action != "amendment" {
+			continue
+		}
+		id, err := strconv.Atoi(h.Params["outcome"])
+		if err != nil || id < 1 || id > len(history) || history[id-1].Action != "outcome" {
+			continue
+		}
+		target := cloneOutcomeEntry(history[id-1])
+		for _, a := range h.Amendments {
+			if a.To == "" {
+				delete(target.Params, a.Field)
+			} else {
+				target.Params[a.Field] = a.To
+			}
+			target.Amendments = append(target.Amendments, a)
+		}
+		history[id-1] = target
+	}
+}
+
+// RecordedOutcome is an outcome entry together with the practice it evaluated.
+type RecordedOutcome struct {
+	// ID is the entry's 1-based index in the full history
+	ID     int
+	Entry  HistoryEntry
+	Target *OutcomeTarget
+}
+
+// CollectOutcomes lists outcomes in history, oldest first.
+func CollectOutcomes(history []HistoryEntry) []RecordedOutcome {
+	evaluated := map[int]OutcomeTarget{}
+	for _, t := range OutcomeTargets(history) {
+		if t.Outcome >= 0 {
+			evaluated[t.Outcome] = t
+		}
+	}
+	var records []RecordedOutcome
+	for i, h := range history {
+		if h.Action != "outcome" {
+			continue
+		}
+		r := RecordedOutcome{ID: i + 1, Entry: h}
+		if t, ok := evaluated[i]; ok {
+			r.Target = &t
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func FormatOutcomeList(records []RecordedOutcome) string {
+	if len(records) == 0 {
+		return "No outcomes recorded."
+	}
+	var b strings.Builder
+	for _, r := range records {
+		h := r.Entry
+		evaluated := h.Params["stratagem"]
+		if r.Target != nil {
+			evaluated = r.Target.String()
+		}
+		b.WriteString(fmt.Sprintf("#%d [%s] %s: %s\n", r.ID, h.Timestamp, h.Params["result"], evaluated))
+		if shift := h.Params["shift"]; shift != "" {
+			b.WriteString(fmt.Sprintf("  Shift: %s\n", shift))
+		}
+		for _, a := range h.Amendments {
+			line := fmt.Sprintf("  Amended %s: %s %s -> %s", a.Timestamp, a.Field, amendedValue(a.From), amendedValue(a.To))
+			if a.Reason != "" {
+				line += fmt.Sprintf(" (%s)", a.Reason)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+func amendedValue(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", v)
+}
+
 var outcomeResult string
 var outcomeShift string
 var outcomeAmend bool
+var outcomeTarget string
 
 var outcomeCmd = &cobra.Command{
 	Use:   "outcome",
@@ -140,22 +266,91 @@ var outcomeCmd = &cobra.Command{
 		sm := DefaultStateManager()
 		var output string
 		err := sm.SaveWithLock(func(s *State) error {
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
 			if outcomeAmend {
 				err := AmendOutcome(s, outcomeResult, outcomeShift)
 				if err != nil {
 					return err
 				}
-				output = fmt.Sprintf("Outcome amended to %s.", outcomeResult)
+				output = fmt.Sprintf("Outcome amended to %s.", outcomeResult) + CommitmentReminder(s, merged.History)
 				return nil
 			}
 
-			err := RecordOutcome(s, outcomeResult, outcomeShift)
+			t, err := RecordOutcomeFor(s, merged.History, outcomeResult, outcomeShift, outcomeTarget)
+			if err != nil {
+				return err
+			}
+			output = fmt.Sprintf("Outcome recorded: %s (%s).", outcomeResult, t.Name) + CommitmentReminder(s, merged.History)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+var outcomePendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List completed runs and freestyle practice that have no outcome yet",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		s, err := sm.Load()
+		if err != nil {
+			return err
+		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		pending := PendingOutcomes(OutcomeTargets(merged.History), outcomePendingUnfinished)
+		fmt.Println(FormatOutput(jsonOutput, FormatPendingOutcomes(pending, merged.History), nil))
+		return nil
+	},
+}
+
+var outcomeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded outcomes with their IDs and amendments",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		s, err := sm.Load()
+		if err != nil {
+			return err
+		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, FormatOutcomeList(CollectOutcomes(merged.History)), nil))
+		return nil
+	},
+}
+
+var outcomePendingUnfinished bool
+var outcomeAmendReason string
+
+var outcomeAmendCmd = &cobra.Command{
+	Use:   "amend <id>",
+	Short: "Revise the result or shift of any recorded outcome",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		var output string
+		err := sm.SaveWithLock(func(s *State) error {
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			r, err := AmendOutcomeAt(s, merged.History, args[0], outcomeResult, outcomeShift, cmd.Flags().Changed("shift"), outcomeAmendReason)
 			if err != nil {
 				return err
 			}
-			// Find what was just recorded
-			last := s.History[len(s.History)-1]
-			output = fmt.Sprintf("Outcome recorded: %s (%s).", outcomeResult, last.Params["stratagem"])
+			output = fmt.Sprintf("Outcome #%d amended: now %s.", r.ID, r.Entry.Params["result"])
 			return nil
 		})
 		if err != nil {
@@ -170,6 +365,12 @@ func init() {
 	outcomeCmd.Flags().StringVar(&outcomeResult, "result", "", "Outcome: productive or unproductive (required)")
 	outcomeCmd.Flags().StringVar(&outcomeShift, "shift", "", "Description of what changed (optional)")
 	outcomeCmd.Flags().BoolVar(&outcomeAmend, "amend", false, "Update most recent outcome instead of creating new")
+	outcomeCmd.Flags().StringVar(&outcomeTarget, "target", "", "What to evaluate: a run ID, a history index (#N), last-stratagem or last-freestyle (default: latest completed run without an outcome, else latest freestyle)")
 	outcomeCmd.MarkFlagRequired("result")
+	outcomePendingCmd.Flags().BoolVar(&outcomePendingUnfinished, "unfinished", false, "Include aborted and abandoned runs, which take an outcome when named with --target")
+	outcomeAmendCmd.Flags().StringVar(&outcomeResult, "result", "", "New result: productive or unproductive")
+	outcomeAmendCmd.Flags().StringVar(&outcomeShift, "shift", "", "New shift description (empty clears it)")
+	outcomeAmendCmd.Flags().StringVar(&outcomeAmendReason, "reason", "", "Why the verdict changed")
+	outcomeCmd.AddCommand(outcomePendingCmd, outcomeListCmd, outcomeAmendCmd)
 	rootCmd.AddCommand(outcomeCmd)
 }
@@ -269,3 +269,102 @@ func TestOutcomeInvalidResult(t *testing.T) {
 		t.Error("expected error for invalid result value")
 	}
 }
+
+func TestOutcomeAmendRecordsAuditTrail(t *testing.T) {
+	s := NewState()
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
+	RecordOutcome(s, "productive", "saw it")
+	AmendOutcome(s, "unproductive", "")
+
+	h := s.History[1]
+	if _, ok := h.Params["shift"]; ok {
+		t.Error("amending with an empty shift should clear it")
+	}
+	if len(h.Amendments) != 2 || h.Amendments[0].Field != "result" || h.Amendments[0].From != "productive" || h.Amendments[1].From != "saw it" {
+		t.Errorf("expected result and shift amendments, got %+v", h.Amendments)
+	}
+}
+
+func TestAmendOutcomeAt(t *testing.T) {
+	s := NewState()
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
+	RecordOutcome(s, "productive", "")
+	applyFeel(s, "chest", "warm", "o", "")
+	RecordOutcome(s, "unproductive", "")
+
+	// One archived entry ahead of the live history
+	archived := HistoryEntry{Action: "outcome", Params: map[string]string{"result": "productive"}}
+	history := append([]HistoryEntry{archived}, s.History...)
+
+	r, err := AmendOutcomeAt(s, history, "#3", "unproductive", "", false, "it faded")
+	if err != nil {
+		t.Fatalf("amend: %v", err)
+	}
+	if r.ID != 3 || s.History[1].Params["result"] != "unproductive" || s.History[3].Params["result"] != "unproductive" {
+		t.Errorf("expected only the older outcome revised, got %+v", s.History)
+	}
+	if a := s.History[1].Amendments; len(a) != 1 || a[0].Reason != "it faded" || a[0].To != "unproductive" {
+		t.Errorf("expected an audit entry with the reason, got %+v", a)
+	}
+
+	cases := map[string]string{
+		"3": "already says that",
+		"4": "no outcome #4",
+		"x": "no outcome #x",
+	}
+	for ref, want := range cases {
+		if _, err := AmendOutcomeAt(s, history, ref, "unproductive", "", false, ""); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected %q error, got %v", ref, want, err)
+		}
+	}
+	if _, err := AmendOutcomeAt(s, history, "3", "", "", false, ""); err == nil {
+		t.Error("expected error when neither result nor shift is given")
+	}
+}
+
+func TestAmendArchivedOutcome(t *testing.T) {
+	s := NewState()
+	archived := HistoryEntry{Action: "outcome", Params: map[string]string{"result": "productive"}}
+	history := append([]HistoryEntry{archived}, s.History...)
+
+	r, err := AmendOutcomeAt(s, history, "1", "unproductive", "", false, "it faded")
+	if err != nil {
+		t.Fatalf("amend archived: %v", err)
+	}
+	if r.ID != 1 || r.Entry.Params["result"] != "unproductive" {
+		t.Errorf("expected #1 now unproductive, got %+v", r)
+	}
+	if archived.Params["result"] != "productive" {
+		t.Error("the archived entry itself should not change")
+	}
+	last := s.History[len(s.History)-1]
+	if last.Action != "amendment" || last.Params["outcome"] != "1" || len(last.Amendments) != 1 || last.Amendments[0].Reason != "it faded" {
+		t.Fatalf("expected an amendment entry in live history, got %+v", last)
+	}
+
+	merged := append([]HistoryEntry{archived}, s.History...)
+	applyOutcomeAmendments(merged)
+	if merged[0].Params["result"] != "unproductive" || len(merged[0].Amendments) != 1 {
+		t.Errorf("amendment should fold into the archived outcome, got %+v", merged[0])
+	}
+	if _, err := AmendOutcomeAt(s, merged, "1", "unproductive", "", false, ""); err == nil || !strings.Contains(err.Error(), "already says that") {
+		t.Errorf("expected already-says-that against the folded history, got %v", err)
+	}
+}
+
+func TestFormatOutcomeList(t *testing.T) {
+	s := NewState()
+	completedRun(s, "pivot", "aaaa1111", "Ada")
+	RecordOutcome(s, "productive", "reframed")
+	AmendOutcomeAt(s, s.History, "4", "unproductive", "", false, "relapsed")
+
+	out := FormatOutcomeList(CollectOutcomes(s.History))
+	for _, want := range []string{"#4 [", "unproductive: pivot run aaaa1111 (completed)", "Shift: reframed", `result "productive" -> "unproductive" (relapsed)`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if FormatOutcomeList(nil) != "No outcomes recorded." {
+		t.Error("expected empty message")
+	}
+}
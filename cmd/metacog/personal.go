@@ -0,0 +1,460 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeTags appends add to tags, trimming and lowercasing, without duplicates.
+func mergeTags(tags, add []string) []string {
+	out := append([]string{}, tags...)
+	for _, t := range add {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !containsString(out, t) {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func removeTags(tags, remove []string) []string {
+	var out []string
+	for _, t := range tags {
+		if !containsString(remove, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// findPersonalStance resolves ref as a 1-based list number or an exact who.
+// A who shared by several stances is ambiguous and must be given by number.
+func findPersonalStance(stances []PersonalStance, ref string) (int, error) {
+	if len(stances) == 0 {
+		return -1, fmt.Errorf("no personal stances. Save one with 'metacog inspire --save'")
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(stances) {
+			return -1, fmt.Errorf("no personal stance #%d (have %d)", n, len(stances))
+		}
+		return n - 1, nil
+	}
+	idx := -1
+	for i, st := range stances {
+		if st.Who == ref {
+			if idx >= 0 {
+				return -1, fmt.Errorf("several personal stances are %q; refer to one by number (see 'metacog inspire personal list')", ref)
+			}
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return -1, fmt.Errorf("no personal stance %q. Run 'metacog inspire personal list'", ref)
+	}
+	return idx, nil
+}
+
+// updatePersonalPool rewrites the personal pool under the pool lock.
+func updatePersonalPool(metacogDir string, fn func([]PersonalStance) ([]PersonalStance, error)) error {
+	return withPoolLock(metacogDir, func() error {
+		stances, err := readUserPool(userPoolPath(metacogDir, PersonalPool))
+		if err != nil {
+			return err
+		}
+		updated, err := fn(stances)
+		if err != nil {
+			return err
+		}
+		return writeUserPool(metacogDir, PersonalPool, updated)
+	})
+}
+
+func LoadPersonalStances(metacogDir string) ([]PersonalStance, error) {
+	return readUserPool(userPoolPath(metacogDir, PersonalPool))
+}
+
+func RemovePersonalStance(metacogDir, ref string) (PersonalStance, error) {
+	var removed PersonalStance
+	err := updatePersonalPool(metacogDir, func(stances []PersonalStance) ([]PersonalStance, error) {
+		idx, err := findPersonalStance(stances, ref)
+		if err != nil {
+			return nil, err
+		}
+		removed = stances[idx]
+		return append(stances[:idx], stances[idx+1:]...), nil
+	})
+	return removed, err
+}
+
+// StanceEdit holds the fields to change; nil leaves a field as is.
+type StanceEdit struct {
+	Who, Where, Lens, Note, Substance, Method, Qualia *string
+}
+
+// EditPersonalStance applies edit and returns the stance's 1-based number with its new fields.
+func EditPersonalStance(metacogDir, ref string, edit StanceEdit) (int, PersonalStance, error) {
+	var edited PersonalStance
+	var number int
+	err := updatePersonalPool(metacogDir, func(stances []PersonalStance) ([]PersonalStance, error) {
+		idx, err := findPersonalStance(stances, ref)
+		if err != nil {
+			return nil, err
+		}
+		st := stances[idx]
+		for _, f := range []struct {
+			dst *string
+			src *string
+		}{
+			{&st.Who, edit.Who}, {&st.Where, edit.Where}, {&st.Lens, edit.Lens}, {&st.Note, edit.Note},
+			{&st.Substance, edit.Substance}, {&st.Method, edit.Method}, {&st.Qualia, edit.Qualia},
+		} {
+			if f.src != nil {
+				*f.dst = strings.TrimSpace(*f.src)
+			}
+		}
+		if st.Substance == "" {
+			if (edit.Method != nil && st.Method != "") || (edit.Qualia != nil && st.Qualia != "") {
+				return nil, fmt.Errorf("stance has no substrate; set --substance along with --method and --qualia")
+			}
+			st.Method, st.Qualia = "", ""
+		}
+		if st.Who == "" || st.Where == "" || st.Lens == "" {
+			return nil, fmt.Errorf("who, where and lens can't be empty")
+		}
+		if st.Substance != "" && (st.Method == "" || st.Qualia == "") {
+			return nil, fmt.Errorf("a substrate needs substance, method and qualia")
+		}
+		for i, other := range stances {
+			if i != idx && other.sameComposite(st) {
+				return nil, fmt.Errorf("edit would duplicate personal stance #%d; remove one instead", i+1)
+			}
+		}
+		stances[idx] = st
+		edited = st
+		number = idx + 1
+		return stances, nil
+	})
+	return number, edited, err
+}
+
+func TagPersonalStance(metacogDir, ref string, tags []string, remove bool) (PersonalStance, error) {
+	var tagged PersonalStance
+	err := updatePersonalPool(metacogDir, func(stances []PersonalStance) ([]PersonalStance, error) {
+		idx, err := findPersonalStance(stances, ref)
+		if err != nil {
+			return nil, err
+		}
+		if remove {
+			stances[idx].Tags = removeTags(stances[idx].Tags, mergeTags(nil, tags))
+		} else {
+			stances[idx].Tags = mergeTags(stances[idx].Tags, tags)
+		}
+		tagged = stances[idx]
+		return stances, nil
+	})
+	return tagged, err
+}
+
+func formatPersonalStance(n int, st PersonalStance) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d. %s\n", n, st.Who))
+	b.WriteString(fmt.Sprintf("   Where: %s\n   Lens: %s\n", st.Where, st.Lens))
+	if st.Substance != "" {
+		b.WriteString(fmt.Sprintf("   Substrate: %s (%s; %s)\n", st.Substance, st.Method, st.Qualia))
+	}
+	if layers := len(st.Layers) + len(st.Substrates); layers > 0 {
+		b.WriteString(fmt.Sprintf("   Composite: %d lower layers\n", layers))
+	}
+	if len(st.Tags) > 0 {
+		b.WriteString(fmt.Sprintf("   Tags: %s\n", strings.Join(st.Tags, ", ")))
+	}
+	if st.Note != "" {
+		b.WriteString(fmt.Sprintf("   Note: %s\n", st.Note))
+	}
+	if st.CreatedAt != "" {
+		saved := "   Saved: " + st.CreatedAt
+		if st.Session != "" {
+			saved += fmt.Sprintf(" in session %q", st.Session)
+		}
+		if st.Stratagem != "" {
+			saved += " during " + st.Stratagem
+			if st.StratagemRun != "" {
+				saved += fmt.Sprintf(" (run %s)", st.StratagemRun)
+			}
+		}
+		b.WriteString(saved + "\n")
+	}
+	return b.String()
+}
+
+// FormatPersonalStances lists stances with their pool numbers, keeping only
+// those carrying tag when it is set.
+func FormatPersonalStances(stances []PersonalStance, tag string) string {
+	if len(stances) == 0 {
+		return "No personal stances. Save one with 'metacog inspire --save'."
+	}
+	var b strings.Builder
+	shown := 0
+	for i, st := range stances {
+		if tag != "" && !containsString(st.Tags, strings.ToLower(tag)) {
+			continue
+		}
+		b.WriteString(formatPersonalStance(i+1, st))
+		shown++
+	}
+	if shown == 0 {
+		return fmt.Sprintf("No personal stances tagged %q.", tag)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var (
+	personalTag             string
+	personalTagRemove       bool
+	personalByEffectiveness bool
+	personalEdit            struct {
+		who, where, lens, note, substance, method, qualia string
+	}
+)
+
+var inspirePersonalCmd = &cobra.Command{
+	Use:   "personal",
+	Short: "List, remove, edit and tag personal stances",
+}
+
+var inspirePersonalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List personal stances with their numbers",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		stances, err := LoadPersonalStances(sm.dir)
+		if err != nil {
+			return err
+		}
+		if personalByEffectiveness {
+			s, err := sm.Load()
+			if err != nil {
+				return err
+			}
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			fmt.Println(FormatOutput(jsonOutput, FormatPersonalEffectiveness(stances, merged.History), nil))
+			return nil
+		}
+		fmt.Println(FormatOutput(jsonOutput, FormatPersonalStances(stances, personalTag), nil))
+		return nil
+	},
+}
+
+var inspirePersonalRemoveCmd = &cobra.Command{
+	Use:   "remove [number|who]",
+	Short: "Remove a personal stance",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := RemovePersonalStance(DefaultStateManager().dir, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Removed personal stance: %s", removed.Who), nil))
+		return nil
+	},
+}
+
+var inspirePersonalEditCmd = &cobra.Command{
+	Use:   "edit [number|who]",
+	Short: "Change fields of a personal stance",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var edit StanceEdit
+		flags := cmd.Flags()
+		for name, pair := range map[string]struct {
+			dst **string
+			val *string
+		}{
+			"who":       {&edit.Who, &personalEdit.who},
+			"where":     {&edit.Where, &personalEdit.where},
+			"lens":      {&edit.Lens, &personalEdit.lens},
+			"note":      {&edit.Note, &personalEdit.note},
+			"substance": {&edit.Substance, &personalEdit.substance},
+			"method":    {&edit.Method, &personalEdit.method},
+			"qualia":    {&edit.Qualia, &personalEdit.qualia},
+		} {
+			if flags.Changed(name) {
+				*pair.dst = pair.val
+			}
+		}
+		if edit == (StanceEdit{}) {
+			return fmt.Errorf("nothing to change. Pass at least one of --who, --where, --lens, --note, --substance, --method, --qualia")
+		}
+		n, edited, err := EditPersonalStance(DefaultStateManager().dir, args[0], edit)
+		if err != nil {
+			return err
+		}
+		output := "Updated personal stance:\n" + strings.TrimRight(formatPersonalStance(n, edited), "\n")
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+var inspirePersonalTagCmd = &cobra.Command{
+	Use:   "tag [number|who] [tag...]",
+	Short: "Add tags to a personal stance (or remove them with --remove)",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagged, err := TagPersonalStance(DefaultStateManager().dir, args[0], args[1:], personalTagRemove)
+		if err != nil {
+			return err
+		}
+		tags := "none"
+		if len(tagged.Tags) > 0 {
+			tags = strings.Join(tagged.Tags, ", ")
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("%s tags: %s", tagged.Who, tags), nil))
+		return nil
+	},
+}
+
+func init() {
+	inspirePersonalListCmd.Flags().StringVar(&personalTag, "tag", "", "Only list stances with this tag")
+	inspirePersonalListCmd.Flags().BoolVar(&personalByEffectiveness, "by-effectiveness", false, "Order by productive-outcome rate of the runs that used each identity")
+	inspirePersonalTagCmd.Flags().BoolVar(&personalTagRemove, "remove", false, "Remove the given tags instead of adding them")
+	f := inspirePersonalEditCmd.Flags()
+	f.StringVar(&personalEdit.who, "who", "", "New name")
+	f.StringVar(&personalEdit.where, "where", "", "New environment")
+	f.StringVar(&personalEdit.lens, "lens", "", "New lens")
+	f.StringVar(&personalEdit.note, "note", "", "New note (empty string clears it)")
+	f.StringVar(&personalEdit.substance, "substance", "", "New substrate substance (empty string clears the substrate)")
+	f.StringVar(&personalEdit.method, "method", "", "New substrate method")
+	f.StringVar(&personalEdit.qualia, "qualia", "", "New substrate qualia")
+	inspirePersonalCmd.AddCommand(inspirePersonalListCmd)
+	inspirePersonalCmd.AddCommand(inspirePersonalRemoveCmd)
+	inspirePersonalCmd.AddCommand(inspirePersonalEditCmd)
+	inspirePersonalCmd.AddCommand(inspirePersonalTagCmd)
+	inspireCmd.AddCommand(inspirePersonalCmd)
+}
+
+// originatingRun names the stratagem run an identity was taken up in: the
+// active run, or the run whose span held the identity's most recent become.
+func originatingRun(s *State, who string) (string, string) {
+	if s.Stratagem != nil {
+		return s.Stratagem.Name, s.Stratagem.RunID
+	}
+	for i := len(s.History) - 1; i >= 0; i-- {
+		h := s.History[i]
+		if h.Action != "become" || h.Params["name"] != who {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			prev := s.History[j]
+			if prev.Action != "stratagem" {
+				continue
+			}
+			if prev.Params["event"] == "started" {
+				return prev.Params["name"], prev.Params["run"]
+			}
+			return "", ""
+		}
+		return "", ""
+	}
+	return "", ""
+}
+
+// OutcomeRecord counts the outcomes recorded after an identity was used.
+type OutcomeRecord struct {
+	Productive int
+	Total      int
+}
+
+func (r OutcomeRecord) Rate() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Productive) / float64(r.Total)
+}
+
+// Weight smooths the productive rate so untried identities still get drawn.
+func (r OutcomeRecord) Weight() float64 {
+	return float64(r.Productive+1) / float64(r.Total+2)
+}
+
+// IdentityOutcomes credits each outcome to the identities become'd in the
+// run or freestyle burst it evaluates, keyed by lowercased name. Becomes in
+// aborted or abandoned runs are never credited.
+func IdentityOutcomes(history []HistoryEntry) map[string]OutcomeRecord {
+	records := map[string]OutcomeRecord{}
+	for _, t := range OutcomeTargets(history) {
+		if t.Outcome < 0 || (t.Kind == TargetStratagem && t.Status != "completed") {
+			continue
+		}
+		productive := history[t.Outcome].Params["result"] == "productive"
+		credited := map[string]bool{}
+		for _, idx := range t.Primitives {
+			name := strings.ToLower(history[idx].Params["name"])
+			if history[idx].Action != "become" || name == "" || credited[name] {
+				continue
+			}
+			credited[name] = true
+			r := records[name]
+			r.Total++
+			if productive {
+				r.Productive++
+			}
+			records[name] = r
+		}
+	}
+	return records
+}
+
+// OutcomeWeights turns identity outcomes into stance weights for a draw.
+func OutcomeWeights(history []HistoryEntry) map[string]float64 {
+	weights := map[string]float64{}
+	for name, r := range IdentityOutcomes(history) {
+		weights[name] = r.Weight()
+	}
+	return weights
+}
+
+// FormatPersonalEffectiveness lists stances by productive-outcome rate, most
+// effective first; stances never followed by an outcome come last.
+func FormatPersonalEffectiveness(stances []PersonalStance, history []HistoryEntry) string {
+	if len(stances) == 0 {
+		return "No personal stances. Save one with 'metacog inspire --save'."
+	}
+	records := IdentityOutcomes(history)
+	order := make([]int, len(stances))
+	for i := range order {
+		order[i] = i
+	}
+	rec := func(i int) OutcomeRecord { return records[strings.ToLower(stances[i].Who)] }
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := rec(order[a]), rec(order[b])
+		if (ra.Total == 0) != (rb.Total == 0) {
+			return rb.Total == 0
+		}
+		if ra.Rate() != rb.Rate() {
+			return ra.Rate() > rb.Rate()
+		}
+		return ra.Total > rb.Total
+	})
+
+	var b strings.Builder
+	for _, i := range order {
+		r := rec(i)
+		b.WriteString(formatPersonalStance(i+1, stances[i]))
+		if r.Total == 0 {
+			b.WriteString("   Outcomes: none recorded\n")
+		} else {
+			b.WriteString(fmt.Sprintf("   Outcomes: %d/%d productive (%.0f%%)\n", r.Productive, r.Total, r.Rate()*100))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func seedPersonal(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, n := range names {
+		s := NewState()
+		applyBecome(s, n, "lens-"+n, "env-"+n)
+		if _, err := SavePersonalStance(dir, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindPersonalStance(t *testing.T) {
+	stances := []PersonalStance{{Who: "Ada"}, {Who: "Zed"}, {Who: "Zed"}}
+	if i, err := findPersonalStance(stances, "2"); err != nil || i != 1 {
+		t.Errorf("expected index 1 for #2, got %d %v", i, err)
+	}
+	if i, err := findPersonalStance(stances, "Ada"); err != nil || i != 0 {
+		t.Errorf("expected index 0 for Ada, got %d %v", i, err)
+	}
+	if _, err := findPersonalStance(stances, "Zed"); err == nil || !strings.Contains(err.Error(), "by number") {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+	if _, err := findPersonalStance(stances, "4"); err == nil {
+		t.Error("expected out-of-range error")
+	}
+	if _, err := findPersonalStance(nil, "1"); err == nil {
+		t.Error("expected error for empty pool")
+	}
+}
+
+func TestRemovePersonalStance(t *testing.T) {
+	dir := t.TempDir()
+	seedPersonal(t, dir, "Ada", "Zed")
+	removed, err := RemovePersonalStance(dir, "Ada")
+	if err != nil || removed.Who != "Ada" {
+		t.Fatalf("remove failed: %+v %v", removed, err)
+	}
+	stances, _ := LoadPersonalStances(dir)
+	if len(stances) != 1 || stances[0].Who != "Zed" {
+		t.Errorf("expected only Zed left, got %+v", stances)
+	}
+}
+
+func TestEditPersonalStance(t *testing.T) {
+	dir := t.TempDir()
+	seedPersonal(t, dir, "Ada", "Zed")
+	lens, note := "poetical science", "kept for design reviews"
+	n, edited, err := EditPersonalStance(dir, "Ada", StanceEdit{Lens: &lens, Note: &note})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || edited.Lens != lens || edited.Note != note || edited.Where != "env-Ada" {
+		t.Errorf("unexpected edit result #%d %+v", n, edited)
+	}
+
+	empty := ""
+	if _, _, err := EditPersonalStance(dir, "1", StanceEdit{Who: &empty}); err == nil {
+		t.Error("expected error when clearing who")
+	}
+	tea := "tea"
+	if _, _, err := EditPersonalStance(dir, "1", StanceEdit{Substance: &tea}); err == nil {
+		t.Error("expected error for substrate without method and qualia")
+	}
+	method := "steeped"
+	if _, _, err := EditPersonalStance(dir, "1", StanceEdit{Method: &method}); err == nil || !strings.Contains(err.Error(), "no substrate") {
+		t.Errorf("expected method without a substance to be refused, got %v", err)
+	}
+	zed, zedEnv, zedLens := "Zed", "env-Zed", "lens-Zed"
+	if _, _, err := EditPersonalStance(dir, "1", StanceEdit{Who: &zed, Where: &zedEnv, Lens: &zedLens}); err == nil {
+		t.Error("expected error when an edit duplicates another stance")
+	}
+}
+
+func TestTagPersonalStance(t *testing.T) {
+	dir := t.TempDir()
+	seedPersonal(t, dir, "Ada")
+	tagged, err := TagPersonalStance(dir, "1", []string{"Ops", "debug", "ops"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(tagged.Tags, ",") != "ops,debug" {
+		t.Errorf("expected normalized deduped tags, got %v", tagged.Tags)
+	}
+	tagged, _ = TagPersonalStance(dir, "1", []string{"OPS"}, true)
+	if strings.Join(tagged.Tags, ",") != "debug" {
+		t.Errorf("expected ops removed, got %v", tagged.Tags)
+	}
+}
+
+func TestSaveStanceMergesTagsOnDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	seedPersonal(t, dir, "Ada")
+	saved, err := SaveStance(dir, PersonalPool, PersonalStance{Who: "Ada", Where: "env-Ada", Lens: "lens-Ada", Tags: []string{"x"}, Note: "why"})
+	if err != nil || saved {
+		t.Fatalf("expected duplicate merge, got saved=%v err=%v", saved, err)
+	}
+	stances, _ := LoadPersonalStances(dir)
+	if len(stances) != 1 || stances[0].Note != "why" || len(stances[0].Tags) != 1 {
+		t.Errorf("expected tags and note merged into existing stance, got %+v", stances)
+	}
+}
+
+func TestFormatPersonalStancesTagFilter(t *testing.T) {
+	stances := []PersonalStance{
+		{Who: "Ada", Where: "w", Lens: "l", Tags: []string{"ops"}, Note: "n"},
+		{Who: "Zed", Where: "w", Lens: "l"},
+	}
+	out := FormatPersonalStances(stances, "ops")
+	if !strings.Contains(out, "1. Ada") || strings.Contains(out, "Zed") || !strings.Contains(out, "Note: n") {
+		t.Errorf("unexpected filtered list: %q", out)
+	}
+	if out := FormatPersonalStances(stances, "nope"); !strings.Contains(out, "No personal stances tagged") {
+		t.Errorf("unexpected empty filter output: %q", out)
+	}
+}
+
+func TestStanceFromStateRecordsProvenance(t *testing.T) {
+	s := NewState()
+	s.Session = "deep-work"
+	StartStratagem(s, "mirror", false)
+	applyBecome(s, "Ada", "l", "e")
+	st, err := StanceFromState(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.CreatedAt == "" || st.Session != "deep-work" || st.SessionID != s.SessionID {
+		t.Errorf("expected time and session recorded, got %+v", st)
+	}
+	if st.Stratagem != "mirror" || st.StratagemRun == "" || st.StratagemRun != s.Stratagem.RunID {
+		t.Errorf("expected originating run recorded, got %+v", st)
+	}
+}
+
+func TestOriginatingRunAfterCompletion(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "mirror", false)
+	run := s.Stratagem.RunID
+	applyBecome(s, "Ada", "l", "e")
+	AbortStratagem(s)
+	if name, id := originatingRun(s, "Ada"); name != "mirror" || id != run {
+		t.Errorf("expected become traced to run %s, got %s %s", run, name, id)
+	}
+	applyBecome(s, "Zed", "l", "e")
+	if name, _ := originatingRun(s, "Zed"); name != "" {
+		t.Errorf("freestyle become should have no run, got %s", name)
+	}
+}
+
+func TestIdentityOutcomes(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "pivot", false)
+	applyBecome(s, "Aborted", "l", "e")
+	AbortStratagem(s)
+	applyBecome(s, "Free", "l", "e")
+	RecordOutcome(s, "productive", "")
+
+	StartStratagem(s, "pivot", false)
+	applyBecome(s, "Run", "l", "e")
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
+	s.Stratagem = nil
+	RecordOutcome(s, "unproductive", "")
+
+	records := IdentityOutcomes(s.History)
+	if r := records["free"]; r.Productive != 1 || r.Total != 1 {
+		t.Errorf("freestyle outcome should credit Free, got %+v", r)
+	}
+	if r := records["run"]; r.Productive != 0 || r.Total != 1 {
+		t.Errorf("stratagem outcome should credit Run, got %+v", r)
+	}
+	if r, ok := records["aborted"]; ok {
+		t.Errorf("aborted run should not be credited, got %+v", r)
+	}
+
+	weights := OutcomeWeights(s.History)
+	if weights["free"] <= weights["run"] {
+		t.Errorf("productive identity should outweigh unproductive one: %v", weights)
+	}
+}
+
+func TestFormatPersonalEffectivenessOrder(t *testing.T) {
+	stances := []PersonalStance{
+		{Who: "Untried", Where: "w", Lens: "l"},
+		{Who: "Weak", Where: "w", Lens: "l"},
+		{Who: "Strong", Where: "w", Lens: "l"},
+	}
+	history := []HistoryEntry{
+		{Action: "become", Params: map[string]string{"name": "Weak"}},
+		{Action: "outcome", Params: map[string]string{"result": "unproductive", "stratagem": "freestyle"}},
+		{Action: "become", Params: map[string]string{"name": "Strong"}},
+		{Action: "outcome", Params: map[string]string{"result": "productive", "stratagem": "freestyle"}},
+	}
+	out := FormatPersonalEffectiveness(stances, history)
+	strong, weak, untried := strings.Index(out, "3. Strong"), strings.Index(out, "2. Weak"), strings.Index(out, "1. Untried")
+	if !(strong < weak && weak < untried) {
+		t.Errorf("expected Strong, Weak, Untried order, got %q", out)
+	}
+	if !strings.Contains(out, "1/1 productive (100%)") || !strings.Contains(out, "none recorded") {
+		t.Errorf("unexpected effectiveness lines: %q", out)
+	}
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	IssueError   = "error"
+	IssueWarning = "warning"
+)
+
+// PoolIssue is one finding from pool validation. Index is the 1-based stance
+// position in the pool file, or 0 for problems with the file as a whole.
+type PoolIssue struct {
+	Severity string
+	Pool     string
+	Source   string
+	Index    int
+	Message  string
+}
+
+func (i PoolIssue) String() string {
+	loc := fmt.Sprintf("%s (%s)", i.Pool, i.Source)
+	if i.Index > 0 {
+		loc += fmt.Sprintf(" #%d", i.Index)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, loc, i.Message)
+}
+
+type checkedStance struct {
+	pool   string
+	source string
+	index  int
+	stance Stance
+}
+
+func stanceKey(s Stance) string {
+	norm := func(v string) string { return strings.ToLower(strings.TrimSpace(v)) }
+	return norm(s.Who) + "\x00" + norm(s.Where) + "\x00" + norm(s.Lens)
+}
+
+// checkPoolFile parses one pool file strictly: unknown fields, empty
+// who/where/lens and duplicates within the file are errors.
+func checkPoolFile(pool, source string, data []byte) ([]checkedStance, []PoolIssue) {
+	var issues []PoolIssue
+	issue := func(index int, format string, args ...interface{}) {
+		issues = append(issues, PoolIssue{IssueError, pool, source, index, fmt.Sprintf(format, args...)})
+	}
+
+	var stances []Stance
+	if source == PoolSourceEmbedded {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&stances); err != nil {
+			issue(0, "invalid pool file: %v", err)
+			return nil, issues
+		}
+	} else {
+		var personal []PersonalStance
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := dec.Decode(&personal); err != nil {
+				issue(0, "invalid pool file: %v", err)
+				return nil, issues
+			}
+		}
+		for _, ps := range personal {
+			stances = append(stances, Stance{Who: ps.Who, Where: ps.Where, Lens: ps.Lens})
+		}
+	}
+
+	var checked []checkedStance
+	seen := map[string]int{}
+	for i, st := range stances {
+		n := i + 1
+		var empty []string
+		if strings.TrimSpace(st.Who) == "" {
+			empty = append(empty, "who")
+		}
+		if strings.TrimSpace(st.Where) == "" {
+			empty = append(empty, "where")
+		}
+		if strings.TrimSpace(st.Lens) == "" {
+			empty = append(empty, "lens")
+		}
+		if len(empty) > 0 {
+			issue(n, "empty %s", strings.Join(empty, ", "))
+			continue
+		}
+		key := stanceKey(st)
+		if first, dup := seen[key]; dup {
+			issue(n, "duplicate of #%d (%s)", first, st.Who)
+			continue
+		}
+		seen[key] = n
+		checked = append(checked, checkedStance{pool, source, n, st})
+	}
+	return checked, issues
+}
+
+// ValidateEmbeddedPools checks the stance pools compiled into the binary.
+func ValidateEmbeddedPools() []PoolIssue {
+	checked, issues := validateEmbedded()
+	return append(issues, crossPoolDuplicates(checked)...)
+}
+
+func validateEmbedded() ([]checkedStance, []PoolIssue) {
+	entries, err := stancesFS.ReadDir("stances")
+	if err != nil {
+		return nil, []PoolIssue{{IssueError, "stances", PoolSourceEmbedded, 0, err.Error()}}
+	}
+	var all []checkedStance
+	var issues []PoolIssue
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		data, err := stancesFS.ReadFile("stances/" + entry.Name())
+		if err != nil {
+			issues = append(issues, PoolIssue{IssueError, name, PoolSourceEmbedded, 0, err.Error()})
+			continue
+		}
+		checked, fileIssues := checkPoolFile(name, PoolSourceEmbedded, data)
+		all = append(all, checked...)
+		issues = append(issues, fileIssues...)
+	}
+	return all, issues
+}
+
+// ValidateAllPools checks the embedded pools and every user pool file in metacogDir.
+func ValidateAllPools(metacogDir string) []PoolIssue {
+	all, issues := validateEmbedded()
+	for _, name := range ListUserPools(metacogDir) {
+		if err := validatePoolName(name); err != nil {
+			issues = append(issues, PoolIssue{IssueWarning, name, PoolSourceUser, 0, err.Error() + "; the pool loads but can't be managed with 'inspire pool'"})
+		}
+		data, err := os.ReadFile(userPoolPath(metacogDir, name))
+		if err != nil {
+			issues = append(issues, PoolIssue{IssueError, name, PoolSourceUser, 0, err.Error()})
+			continue
+		}
+		checked, fileIssues := checkPoolFile(name, PoolSourceUser, data)
+		all = append(all, checked...)
+		issues = append(issues, fileIssues...)
+	}
+	return append(issues, crossPoolDuplicates(all)...)
+}
+
+// crossPoolDuplicates warns about stances listed in more than one pool file.
+// They are not errors: a figure can belong to several domains, but each copy
+// raises that stance's odds under uniform-stance draws.
+func crossPoolDuplicates(all []checkedStance) []PoolIssue {
+	byKey := map[string][]checkedStance{}
+	var order []string
+	for _, c := range all {
+		key := stanceKey(c.stance)
+		if _, ok := byKey[key]; !ok {
+			order = append(order, key)
+		}
+		byKey[key] = append(byKey[key], c)
+	}
+	var issues []PoolIssue
+	for _, key := range order {
+		copies := byKey[key]
+		if len(copies) < 2 {
+			continue
+		}
+		first := copies[0]
+		for _, c := range copies[1:] {
+			msg := fmt.Sprintf("%s also appears in %s (%s) #%d", c.stance.Who, first.pool, first.source, first.index)
+			if c.pool == first.pool {
+				msg += "; skipped when the pools are merged"
+			}
+			issues = append(issues, PoolIssue{IssueWarning, c.pool, c.source, c.index, msg})
+		}
+	}
+	return issues
+}
+
+func countIssues(issues []PoolIssue, severity string) int {
+	n := 0
+	for _, i := range issues {
+		if i.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+func FormatPoolIssues(issues []PoolIssue) string {
+	errs, warns := countIssues(issues, IssueError), countIssues(issues, IssueWarning)
+	if len(issues) == 0 {
+		return "All stance pools are valid."
+	}
+	lines := make([]string, 0, len(issues)+1)
+	for _, i := range issues {
+		lines = append(lines, i.String())
+	}
+	lines = append(lines, fmt.Sprintf("%d errors, %d warnings.", errs, warns))
+	return strings.Join(lines, "\n")
+}
+
+type poolStat struct {
+	Name    string
+	Source  string
+	Stances int
+	Draws   int
+}
+
+type stanceDraws struct {
+	Who   string
+	Pool  string
+	Count int
+}
+
+// PoolStats counts stances per pool and inspire draws per pool and per stance.
+func PoolStats(pools map[string]StancePool, history []HistoryEntry) ([]poolStat, []stanceDraws, int) {
+	draws := map[string]int{}
+	perStance := map[[2]string]int{}
+	total := 0
+	for _, h := range history {
+		if h.Action != "inspire" || h.Params["kind"] == KindSubstrate {
+			continue
+		}
+		total++
+		draws[h.Params["pool"]]++
+		perStance[[2]string{h.Params["who"], h.Params["pool"]}]++
+	}
+
+	stats := make([]poolStat, 0, len(pools))
+	for _, name := range ListPoolNames(pools) {
+		stats = append(stats, poolStat{name, pools[name].Source, len(pools[name].Stances), draws[name]})
+	}
+
+	top := make([]stanceDraws, 0, len(perStance))
+	for k, n := range perStance {
+		top = append(top, stanceDraws{k[0], k[1], n})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		if top[i].Who != top[j].Who {
+			return top[i].Who < top[j].Who
+		}
+		return top[i].Pool < top[j].Pool
+	})
+	return stats, top, total
+}
+
+func FormatPoolStats(pools map[string]StancePool, history []HistoryEntry) string {
+	stats, top, total := PoolStats(pools, history)
+	stanceTotal := 0
+	width := len("Pool")
+	hasUser := false
+	for _, st := range stats {
+		stanceTotal += st.Stances
+		if len(st.Name)+2 > width {
+			width = len(st.Name) + 2
+		}
+		if st.Source != PoolSourceEmbedded {
+			hasUser = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d pools, %d stances, %d draws recorded.\n\n", len(stats), stanceTotal, total))
+	b.WriteString(fmt.Sprintf("%-*s  %7s  %5s\n", width, "Pool", "Stances", "Draws"))
+	for _, st := range stats {
+		name := st.Name
+		if st.Source != PoolSourceEmbedded {
+			name += " *"
+		}
+		b.WriteString(fmt.Sprintf("%-*s  %7d  %5d\n", width, name, st.Stances, st.Draws))
+	}
+
+	if len(top) > 0 {
+		b.WriteString("\nMost drawn:\n")
+		if len(top) > 10 {
+			top = top[:10]
+		}
+		for _, t := range top {
+			b.WriteString(fmt.Sprintf("  %3d  %s [%s]\n", t.Count, t.Who, t.Pool))
+		}
+	}
+	if hasUser {
+		b.WriteString("\n* includes user stances from $METACOG_HOME/stances\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var inspirePoolValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check embedded and user pools for schema errors, empty fields and duplicates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		issues := ValidateAllPools(DefaultStateManager().dir)
+		fmt.Println(FormatOutput(jsonOutput, FormatPoolIssues(issues), nil))
+		if n := countIssues(issues, IssueError); n > 0 {
+			return fmt.Errorf("%d stance pool errors", n)
+		}
+		return nil
+	},
+}
+
+var inspirePoolStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show stances per pool, draw frequency and most-drawn stances",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		pools, err := LoadStancePoolsWithUser(sm.dir)
+		if err != nil {
+			return err
+		}
+		s, err := sm.Load()
+		if err != nil {
+			return err
+		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, FormatPoolStats(pools, merged.History), nil))
+		return nil
+	},
+}
+
+func init() {
+	inspirePoolCmd.AddCommand(inspirePoolValidateCmd)
+	inspirePoolCmd.AddCommand(inspirePoolStatsCmd)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedStancePoolsValid(t *testing.T) {
+	for _, issue := range ValidateEmbeddedPools() {
+		if issue.Severity == IssueError {
+			t.Error(issue)
+		}
+	}
+}
+
+func TestCheckPoolFileErrors(t *testing.T) {
+	data := []byte(`[
+		{"who": "A", "where": "w", "lens": "l"},
+		{"who": "B", "where": " ", "lens": ""},
+		{"who": "a", "where": "W", "lens": "l "}
+	]`)
+	checked, issues := checkPoolFile("test", PoolSourceEmbedded, data)
+	if len(checked) != 1 {
+		t.Errorf("expected 1 clean stance, got %d", len(checked))
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %v", issues)
+	}
+	if issues[0].Index != 2 || !strings.Contains(issues[0].Message, "where, lens") {
+		t.Errorf("expected empty-field error on #2, got %v", issues[0])
+	}
+	if issues[1].Index != 3 || !strings.Contains(issues[1].Message, "duplicate of #1") {
+		t.Errorf("expected duplicate error on #3, got %v", issues[1])
+	}
+}
+
+func TestCheckPoolFileSchema(t *testing.T) {
+	_, issues := checkPoolFile("test", PoolSourceEmbedded, []byte(`[{"who": "A", "where": "w", "lens": "l", "substance": "x"}]`))
+	if len(issues) != 1 || issues[0].Index != 0 {
+		t.Errorf("embedded pools don't carry substrates; expected schema error, got %v", issues)
+	}
+	_, issues = checkPoolFile("test", PoolSourceUser, []byte(`[{"who": "A", "where": "w", "lens": "l", "substance": "x"}]`))
+	if len(issues) != 0 {
+		t.Errorf("user pools may carry substrates, got %v", issues)
+	}
+	_, issues = checkPoolFile("test", PoolSourceUser, []byte(`{nope`))
+	if len(issues) != 1 || issues[0].Severity != IssueError {
+		t.Errorf("expected error for malformed file, got %v", issues)
+	}
+}
+
+func TestValidateAllPoolsIncludesPersonal(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(userStancesDir(dir), 0755)
+	os.WriteFile(userPoolPath(dir, PersonalPool), []byte(`[
+		{"who": "Audre Lorde", "where": "Sister Outsider", "lens": "the erotic as power"},
+		{"who": "Me", "where": "", "lens": "x"}
+	]`), 0644)
+
+	issues := ValidateAllPools(dir)
+	var personalErr, personalDup bool
+	for _, i := range issues {
+		if i.Pool != PersonalPool {
+			continue
+		}
+		if i.Severity == IssueError && i.Index == 2 {
+			personalErr = true
+		}
+		if i.Severity == IssueWarning && strings.Contains(i.Message, "Audre Lorde also appears") {
+			personalDup = true
+		}
+	}
+	if !personalErr {
+		t.Error("expected empty-field error in personal pool")
+	}
+	if !personalDup {
+		t.Error("expected cross-pool duplicate warning for personal stance matching an embedded one")
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	pools := map[string]StancePool{
+		"a": {Name: "a", Stances: []Stance{{Who: "X"}, {Who: "Y"}}, Source: PoolSourceEmbedded},
+		"b": {Name: "b", Stances: []Stance{{Who: "Z"}}, Source: PoolSourceUser},
+	}
+	history := []HistoryEntry{
+		{Action: "inspire", Params: map[string]string{"pool": "a", "who": "X"}},
+		{Action: "inspire", Params: map[string]string{"pool": "a", "who": "X"}},
+		{Action: "become", Params: map[string]string{"name": "X"}},
+		{Action: "inspire", Params: map[string]string{"pool": "b", "who": "Z"}},
+		{Action: "inspire", Params: map[string]string{"pool": "signals", "kind": KindSubstrate, "substance": "latency"}},
+	}
+	stats, top, total := PoolStats(pools, history)
+	if total != 3 {
+		t.Errorf("expected 3 draws, got %d", total)
+	}
+	if stats[0].Name != "a" || stats[0].Stances != 2 || stats[0].Draws != 2 {
+		t.Errorf("unexpected stats for a: %+v", stats[0])
+	}
+	if top[0].Who != "X" || top[0].Count != 2 {
+		t.Errorf("expected X most drawn, got %+v", top[0])
+	}
+
+	output := FormatPoolStats(pools, history)
+	if !strings.Contains(output, "2 pools, 3 stances, 3 draws recorded.") {
+		t.Errorf("unexpected summary: %q", output)
+	}
+	if !strings.Contains(output, "b *") || !strings.Contains(output, "includes user stances") {
+		t.Errorf("expected user pool marker, got %q", output)
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// PersonalPool is the default target for 'inspire --save'.
+const PersonalPool = "personal"
+
+// User pools live as $METACOG_HOME/stances/NAME.json, one pool per file.
+// A user pool whose name matches an embedded pool extends it: the embedded
+// stances come first and user stances are appended, skipping exact
+// who/where/lens duplicates. Embedded pools are never shadowed or removed.
+
+var poolNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func validatePoolName(name string) error {
+	if !poolNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid pool name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+func userStancesDir(metacogDir string) string {
+	return filepath.Join(metacogDir, "stances")
+}
+
+func userPoolPath(metacogDir, name string) string {
+	return filepath.Join(userStancesDir(metacogDir), name+".json")
+}
+
+// withPoolLock serializes every write to user pool files. It takes the same
+// .personal.lock that personal.json writers have always used, so binaries
+// that only know personal.json still exclude each other.
+func withPoolLock(metacogDir string, fn func() error) error {
+	dir := userStancesDir(metacogDir)
+	os.MkdirAll(dir, 0755)
+	lockFile, err := os.OpenFile(filepath.Join(dir, ".personal.lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open stance pool lock: %w", err)
+	}
+	defer func() {
+		syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+		lockFile.Close()
+	}()
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("cannot acquire stance pool lock: %w", err)
+	}
+	return fn()
+}
+
+// readUserPool returns the stances in a user pool file, or nil if it doesn't exist.
+// A file that exists but can't be parsed is an error, so callers never overwrite it.
+func readUserPool(path string) ([]PersonalStance, error) {
+	return readPoolFile[PersonalStance](path)
+}
+
+// writeUserPool atomically replaces a user pool file. Callers hold the pool lock.
+func writeUserPool(metacogDir, name string, stances []PersonalStance) error {
+	return writePoolFile(userStancesDir(metacogDir), name, stances)
+}
+
+func readPoolFile[T any](path string) ([]T, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read pool %s: %w", path, err)
+	}
+	var items []T
+	if len(data) == 0 {
+		return items, nil
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("pool %s is corrupted (%w); refusing to overwrite. Move it aside or repair it before saving", path, err)
+	}
+	return items, nil
+}
+
+func writePoolFile[T any](dir, name string, items []T) error {
+	if items == nil {
+		items = []T{}
+	}
+	out, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal pool: %w", err)
+	}
+	os.MkdirAll(dir, 0755)
+	tmpPath := filepath.Join(dir, "."+name+".json.tmp")
+	if err := os.WriteFile(tmpPath, out, 0644); err != nil {
+		return fmt.Errorf("cannot write temp pool: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(dir, name+".json")); err != nil {
+		return fmt.Errorf("cannot rename pool: %w", err)
+	}
+	return nil
+}
+
+// listPoolFiles returns the pool names of the *.json files in dir.
+func listPoolFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListUserPools returns the names of the pool files in the user stances directory.
+func ListUserPools(metacogDir string) []string {
+	return listPoolFiles(userStancesDir(metacogDir))
+}
+
+func userPoolExists(metacogDir, name string) bool {
+	_, err := os.Stat(userPoolPath(metacogDir, name))
+	return err == nil
+}
+
+// poolDir is a directory of user pool files: stances or substrates. noun
+// names its pools in errors.
+type poolDir struct {
+	path string
+	noun string
+}
+
+func stancePoolDir(metacogDir string) poolDir {
+	return poolDir{userStancesDir(metacogDir), "user pool"}
+}
+
+func substratePoolDir(metacogDir string) poolDir {
+	return poolDir{userSubstratesDir(metacogDir), "user substrate pool"}
+}
+
+func (d poolDir) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(d.path, name+".json"))
+	return err == nil
+}
+
+func (d poolDir) create(metacogDir, name string) error {
+	if err := validatePoolName(name); err != nil {
+		return err
+	}
+	return withPoolLock(metacogDir, func() error {
+		if d.exists(name) {
+			return fmt.Errorf("pool %q already exists", name)
+		}
+		return writePoolFile[any](d.path, name, nil)
+	})
+}
+
+func (d poolDir) delete(metacogDir, name string) error {
+	if err := validatePoolName(name); err != nil {
+		return err
+	}
+	return withPoolLock(metacogDir, func() error {
+		if !d.exists(name) {
+			return fmt.Errorf("no %s %q (embedded pools can't be deleted)", d.noun, name)
+		}
+		return os.Remove(filepath.Join(d.path, name+".json"))
+	})
+}
+
+func (d poolDir) rename(metacogDir, oldName, newName string) error {
+	if err := validatePoolName(oldName); err != nil {
+		return err
+	}
+	if err := validatePoolName(newName); err != nil {
+		return err
+	}
+	return withPoolLock(metacogDir, func() error {
+		if !d.exists(oldName) {
+			return fmt.Errorf("no %s %q (embedded pools can't be renamed)", d.noun, oldName)
+		}
+		if d.exists(newName) {
+			return fmt.Errorf("pool %q already exists", newName)
+		}
+		return os.Rename(filepath.Join(d.path, oldName+".json"), filepath.Join(d.path, newName+".json"))
+	})
+}
+
+func CreateUserPool(metacogDir, name string) error {
+	return stancePoolDir(metacogDir).create(metacogDir, name)
+}
+
+func DeleteUserPool(metacogDir, name string) error {
+	return stancePoolDir(metacogDir).delete(metacogDir, name)
+}
+
+func RenameUserPool(metacogDir, oldName, newName string) error {
+	return stancePoolDir(metacogDir).rename(metacogDir, oldName, newName)
+}
+
+// CreateSubstratePool, DeleteSubstratePool and RenameSubstratePool manage
+// user substrate pools in $METACOG_HOME/substrates the same way.
+func CreateSubstratePool(metacogDir, name string) error {
+	return substratePoolDir(metacogDir).create(metacogDir, name)
+}
+
+func DeleteSubstratePool(metacogDir, name string) error {
+	return substratePoolDir(metacogDir).delete(metacogDir, name)
+}
+
+func RenameSubstratePool(metacogDir, oldName, newName string) error {
+	return substratePoolDir(metacogDir).rename(metacogDir, oldName, newName)
+}
+
+// LoadStancePoolsWithUser loads the embedded pools and merges in every user pool file.
+func LoadStancePoolsWithUser(metacogDir string) (map[string]StancePool, error) {
+	pools, err := LoadStancePools()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range ListUserPools(metacogDir) {
+		userStances, err := readUserPool(userPoolPath(metacogDir, name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+
+		pool, embedded := pools[name]
+		if !embedded {
+			pool = StancePool{Name: name, Source: PoolSourceUser}
+		} else {
+			pool.Source = PoolSourceExtended
+		}
+		seen := map[Stance]bool{}
+		for _, st := range pool.Stances {
+			seen[st] = true
+		}
+		for _, ps := range userStances {
+			st := Stance{Who: ps.Who, Where: ps.Where, Lens: ps.Lens}
+			if seen[st] {
+				continue
+			}
+			seen[st] = true
+			pool.Stances = append(pool.Stances, st)
+		}
+		pools[name] = pool
+	}
+
+	return pools, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStancePoolFixture(t *testing.T, dir, name, content string) {
+	t.Helper()
+	stancesDir := filepath.Join(dir, "stances")
+	if err := os.MkdirAll(stancesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stancesDir, name+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadUserPoolsEveryFile(t *testing.T) {
+	dir := t.TempDir()
+	writeStancePoolFixture(t, dir, "incident-response", `[{"who":"Pager","where":"3am bridge call","lens":"blast radius"}]`)
+	writeStancePoolFixture(t, dir, "api-design", `[{"who":"Fielding","where":"whiteboard","lens":"constraints"}]`)
+
+	pools, err := LoadStancePoolsWithUser(dir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	for _, name := range []string{"incident-response", "api-design"} {
+		pool, ok := pools[name]
+		if !ok {
+			t.Fatalf("expected user pool %q to load", name)
+		}
+		if pool.Source != PoolSourceUser || len(pool.Stances) != 1 {
+			t.Errorf("pool %q: unexpected %+v", name, pool)
+		}
+	}
+	if pools["philosophy"].Source != PoolSourceEmbedded {
+		t.Errorf("embedded pool should keep embedded source, got %q", pools["philosophy"].Source)
+	}
+}
+
+func TestUserPoolExtendsEmbeddedPool(t *testing.T) {
+	dir := t.TempDir()
+	embedded, _ := LoadStancePools()
+	base := embedded["philosophy"]
+	dup := base.Stances[0]
+	writeStancePoolFixture(t, dir, "philosophy", `[
+		{"who":"`+dup.Who+`","where":"`+dup.Where+`","lens":"`+dup.Lens+`"},
+		{"who":"New Voice","where":"seminar","lens":"aporia"}
+	]`)
+
+	pools, err := LoadStancePoolsWithUser(dir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	pool := pools["philosophy"]
+	if pool.Source != PoolSourceExtended {
+		t.Errorf("expected extended source, got %q", pool.Source)
+	}
+	if len(pool.Stances) != len(base.Stances)+1 {
+		t.Errorf("expected embedded stances plus one new (duplicate skipped), got %d vs %d", len(pool.Stances), len(base.Stances))
+	}
+	if pool.Stances[0] != base.Stances[0] {
+		t.Error("embedded stances should come first")
+	}
+}
+
+func TestLoadSkipsCorruptedUserPool(t *testing.T) {
+	dir := t.TempDir()
+	writeStancePoolFixture(t, dir, "broken", `[{"who":`)
+	pools, err := LoadStancePoolsWithUser(dir)
+	if err != nil {
+		t.Fatalf("load should not fail on a corrupted user pool: %v", err)
+	}
+	if _, ok := pools["broken"]; ok {
+		t.Error("corrupted pool should be skipped")
+	}
+}
+
+func TestSaveStanceToNamedPool(t *testing.T) {
+	dir := t.TempDir()
+	s := &State{Identity: &Identity{Name: "Pager", Lens: "blast radius", Env: "bridge call"}}
+
+	if _, err := SaveStanceToPool(dir, "incident-response", s); err == nil {
+		t.Fatal("expected error saving to a pool that doesn't exist")
+	}
+	if err := CreateUserPool(dir, "incident-response"); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	saved, err := SaveStanceToPool(dir, "incident-response", s)
+	if err != nil || !saved {
+		t.Fatalf("save failed: saved=%v err=%v", saved, err)
+	}
+
+	pools, _ := LoadStancePoolsWithUser(dir)
+	if len(pools["incident-response"].Stances) != 1 {
+		t.Errorf("expected saved stance in pool, got %+v", pools["incident-response"])
+	}
+	if _, ok := pools[PersonalPool]; ok {
+		t.Error("saving to a named pool should not touch personal")
+	}
+}
+
+func TestCreateUserPoolValidation(t *testing.T) {
+	dir := t.TempDir()
+	if err := CreateUserPool(dir, "Bad Name"); err == nil {
+		t.Error("expected error for invalid pool name")
+	}
+	if err := CreateUserPool(dir, "../escape"); err == nil {
+		t.Error("expected error for path-like pool name")
+	}
+	CreateUserPool(dir, "team")
+	if err := CreateUserPool(dir, "team"); err == nil {
+		t.Error("expected error creating an existing pool")
+	}
+}
+
+func TestDeleteUserPoolRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+	os.WriteFile(statePath, []byte("{}"), 0644)
+	CreateUserPool(dir, "draft")
+
+	for _, name := range []string{"../state", "../../etc/passwd", "a/b", ""} {
+		if err := DeleteUserPool(dir, name); err == nil || !strings.Contains(err.Error(), "invalid pool name") {
+			t.Errorf("%q: expected invalid pool name error, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Errorf("state.json should survive: %v", err)
+	}
+}
+
+func TestRenameUserPoolRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "state.json")
+	os.WriteFile(statePath, []byte("{}"), 0644)
+	CreateUserPool(dir, "draft")
+
+	if err := RenameUserPool(dir, "../state", "moved"); err == nil || !strings.Contains(err.Error(), "invalid pool name") {
+		t.Errorf("expected invalid pool name error for the old name, got %v", err)
+	}
+	if err := RenameUserPool(dir, "draft", "../state"); err == nil || !strings.Contains(err.Error(), "invalid pool name") {
+		t.Errorf("expected invalid pool name error for the new name, got %v", err)
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Errorf("state.json should stay put: %v", err)
+	}
+	if _, err := os.Stat(userPoolPath(dir, "moved")); !os.IsNotExist(err) {
+		t.Error("nothing should have been moved into the stances directory")
+	}
+}
+
+func TestRenameAndDeleteUserPool(t *testing.T) {
+	dir := t.TempDir()
+	CreateUserPool(dir, "draft")
+	CreateUserPool(dir, "taken")
+
+	if err := RenameUserPool(dir, "draft", "taken"); err == nil {
+		t.Error("expected error renaming onto an existing pool")
+	}
+	if err := RenameUserPool(dir, "draft", "final"); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	names := strings.Join(ListUserPools(dir), ",")
+	if names != "final,taken" {
+		t.Errorf("expected final,taken, got %s", names)
+	}
+
+	if err := DeleteUserPool(dir, "final"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := DeleteUserPool(dir, "philosophy"); err == nil {
+		t.Error("expected error deleting an embedded pool")
+	}
+	if names := strings.Join(ListUserPools(dir), ","); names != "taken" {
+		t.Errorf("expected only taken left, got %s", names)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// primitiveCommand returns the cobra command registered for a primitive step kind,
+// or nil for THINK/ACTION steps and unknown kinds.
+func primitiveCommand(kind StepKind) *cobra.Command {
+	if kind == StepThink || kind == StepAction {
+		return nil
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == string(kind) {
+			return c
+		}
+	}
+	return nil
+}
+
+// primitiveFlags lists the flags a primitive command defines, in definition order.
+// Inherited persistent flags (--json) and --help are left out.
+func primitiveFlags(cmd *cobra.Command) []*pflag.Flag {
+	fs := cmd.Flags()
+	sorted := fs.SortFlags
+	fs.SortFlags = false
+	defer func() { fs.SortFlags = sorted }()
+
+	inherited := cmd.InheritedFlags()
+	var flags []*pflag.Flag
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" || inherited.Lookup(f.Name) != nil {
+			return
+		}
+		flags = append(flags, f)
+	})
+	return flags
+}
+
+func isRepeatableFlag(f *pflag.Flag) bool {
+	return strings.HasSuffix(f.Value.Type(), "Array") || strings.HasSuffix(f.Value.Type(), "Slice")
+}
+
+// skeletonCommand builds a placeholder command line for a primitive.
+// Repeatable flags are shown twice so the caller sees they take multiple values.
+func skeletonCommand(cmd *cobra.Command) string {
+	parts := []string{"metacog", cmd.Name()}
+	for _, f := range primitiveFlags(cmd) {
+		placeholder := fmt.Sprintf("%q", "<"+f.Name+">")
+		parts = append(parts, "--"+f.Name, placeholder)
+		if isRepeatableFlag(f) {
+			parts = append(parts, "--"+f.Name, placeholder)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func lookupStratagem(name string) (StratagemDef, error) {
+	def, ok := Stratagems[name]
+	if !ok {
+		return StratagemDef{}, fmt.Errorf("unknown stratagem %q. Available: %s", name, strings.Join(allStratagemNames(), ", "))
+	}
+	return def, nil
+}
+
+func FormatStratagemPreview(name string) (string, error) {
+	def, err := lookupStratagem(name)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s — %d steps (preview, state unchanged)\n", def.Name, len(def.Steps)))
+	for i, step := range def.Steps {
+		b.WriteString(fmt.Sprintf("\n%d. [%s] %s\n", i+1, step.Kind, step.Description))
+		cmd := primitiveCommand(step.Kind)
+		if cmd == nil {
+			b.WriteString("   Reflection step: no primitive call. Advance with 'metacog stratagem next'.\n")
+			continue
+		}
+		b.WriteString("   Flags:\n")
+		for _, f := range primitiveFlags(cmd) {
+			kind := f.Value.Type()
+			if isRepeatableFlag(f) {
+				kind += ", repeatable"
+			}
+			b.WriteString(fmt.Sprintf("     --%s (%s) %s\n", f.Name, kind, f.Usage))
+		}
+		b.WriteString(fmt.Sprintf("   $ %s\n", skeletonCommand(cmd)))
+	}
+	return b.String(), nil
+}
+
+func FormatStratagemScript(name string) (string, error) {
+	def, err := lookupStratagem(name)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(fmt.Sprintf("# %s — dry-run walkthrough. Replace every <placeholder> before running.\n", def.Name))
+	b.WriteString("set -e\n\n")
+	b.WriteString(fmt.Sprintf("metacog stratagem start %s\n", name))
+	for i, step := range def.Steps {
+		b.WriteString(fmt.Sprintf("\n# Step %d/%d [%s] %s\n", i+1, len(def.Steps), step.Kind, step.Description))
+		if cmd := primitiveCommand(step.Kind); cmd != nil {
+			b.WriteString(skeletonCommand(cmd) + "\n")
+		}
+		b.WriteString("metacog stratagem next\n")
+	}
+	return b.String(), nil
+}
+
+var stratagemPreviewScript bool
+
+var stratagemPreviewCmd = &cobra.Command{
+	Use:   "preview [name]",
+	Short: "Show every step of a stratagem with the flags each primitive takes",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var output string
+		var err error
+		if stratagemPreviewScript {
+			output, err = FormatStratagemScript(args[0])
+		} else {
+			output, err = FormatStratagemPreview(args[0])
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+func init() {
+	stratagemPreviewCmd.Flags().BoolVar(&stratagemPreviewScript, "as-script", false, "Emit a shell script of placeholder calls interleaved with 'stratagem next'")
+	stratagemPreviewCmd.ValidArgsFunction = completeStratagemNames
+	stratagemCmd.AddCommand(stratagemPreviewCmd)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrimitiveCommandLookup(t *testing.T) {
+	if cmd := primitiveCommand(StepBecome); cmd == nil || cmd.Name() != "become" {
+		t.Fatalf("expected become command, got %v", cmd)
+	}
+	if cmd := primitiveCommand(StepThink); cmd != nil {
+		t.Errorf("THINK steps should have no command, got %s", cmd.Name())
+	}
+}
+
+func TestSkeletonCommandUsesCobraFlags(t *testing.T) {
+	out := skeletonCommand(primitiveCommand(StepBecome))
+	for _, want := range []string{"metacog become", `--name "<name>"`, `--lens "<lens>"`, `--env "<env>"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("skeleton missing %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "--json") {
+		t.Errorf("skeleton should not include inherited flags: %s", out)
+	}
+}
+
+func TestSkeletonCommandRepeatsArrayFlags(t *testing.T) {
+	out := skeletonCommand(primitiveCommand(StepFork))
+	if strings.Count(out, "--threads") != 2 {
+		t.Errorf("expected --threads twice in fork skeleton: %s", out)
+	}
+}
+
+func TestStratagemPreviewListsEveryStep(t *testing.T) {
+	out, err := FormatStratagemPreview("envoy-extreme")
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if !strings.Contains(out, "THE ENVOY EXTREME — 5 steps") {
+		t.Errorf("expected header, got: %s", out)
+	}
+	if strings.Count(out, "$ metacog become") != 3 {
+		t.Errorf("expected 3 become skeletons: %s", out)
+	}
+	if !strings.Contains(out, "--divergence-vector (string)") {
+		t.Errorf("expected fork flag listing: %s", out)
+	}
+}
+
+func TestStratagemPreviewReflectionStep(t *testing.T) {
+	out, err := FormatStratagemPreview("pivot")
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if !strings.Contains(out, "Reflection step") {
+		t.Errorf("expected THINK step note: %s", out)
+	}
+}
+
+func TestStratagemPreviewUnknown(t *testing.T) {
+	if _, err := FormatStratagemPreview("nonexistent"); err == nil {
+		t.Error("expected error for unknown stratagem")
+	}
+}
+
+func TestStratagemScriptInterleavesNext(t *testing.T) {
+	out, err := FormatStratagemScript("pivot")
+	if err != nil {
+		t.Fatalf("script failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "#!/bin/sh\n") {
+		t.Errorf("expected shebang: %s", out)
+	}
+	if !strings.Contains(out, "metacog stratagem start pivot") {
+		t.Errorf("expected start line: %s", out)
+	}
+	if c := strings.Count(out, "metacog stratagem next"); c != len(Stratagems["pivot"].Steps) {
+		t.Errorf("expected %d next calls, got %d", len(Stratagems["pivot"].Steps), c)
+	}
+	if !strings.Contains(out, "metacog drugs --substance") {
+		t.Errorf("expected drugs skeleton: %s", out)
+	}
+}
+
+func TestPreviewDoesNotTouchState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("METACOG_HOME", dir)
+	if _, err := FormatStratagemPreview("chorus"); err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	s, err := NewStateManager(dir).Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if s.Stratagem != nil || len(s.History) != 0 {
+		t.Error("preview should not modify state")
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// User-defined stratagems live in $METACOG_HOME/stratagems.json. They are
+// registered alongside the built-in ones at startup and never shadow them.
+
+const userStratagemsFile = "stratagems"
+
+// UserStratagem is a freestyle sequence promoted to a stratagem, one step per
+// primitive. Productive and Total are its freestyle record when promoted.
+type UserStratagem struct {
+	Key        string   `json:"key"`
+	Steps      []string `json:"steps"`
+	Productive int      `json:"productive"`
+	Total      int      `json:"total"`
+	PromotedAt string   `json:"promoted_at"`
+}
+
+func (u UserStratagem) def() StratagemDef {
+	steps := make([]Step, len(u.Steps))
+	for i, kind := range u.Steps {
+		steps[i] = Step{StepKind(kind), fmt.Sprintf("%s, as in the freestyle practice this was promoted from", kind)}
+	}
+	return StratagemDef{Name: strings.ToUpper(u.Key), Steps: steps}
+}
+
+// validate applies the checks PromoteSequence makes, for entries read back
+// from a stratagems.json that may have been edited by hand.
+func (u UserStratagem) validate() error {
+	if !stratagemKeyPattern.MatchString(u.Key) {
+		return fmt.Errorf("invalid stratagem name %q: use lowercase letters, digits, '-' and '_'", u.Key)
+	}
+	_, err := ParseSequence(strings.Join(u.Steps, " "))
+	return err
+}
+
+// userStratagems marks the keys in Stratagems that came from stratagems.json.
+var userStratagems = map[string]bool{}
+
+var stratagemKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+func userStratagemsPath(metacogDir string) string {
+	return filepath.Join(metacogDir, userStratagemsFile+".json")
+}
+
+// LoadUserStratagems reads stratagems.json; a missing file means none.
+func LoadUserStratagems(metacogDir string) ([]UserStratagem, error) {
+	path := userStratagemsPath(metacogDir)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read user stratagems %s: %w", path, err)
+	}
+	var defs []UserStratagem
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("user stratagems file %s is corrupted (%w); refusing to overwrite. Move it aside or repair it before promoting", path, err)
+	}
+	return defs, nil
+}
+
+// registerUserStratagems adds the user's stratagems to Stratagems. A file
+// that can't be read is reported and skipped so every other command still runs.
+func registerUserStratagems(metacogDir string) {
+	defs, err := LoadUserStratagems(metacogDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: user stratagems not loaded: %v\n", err)
+		return
+	}
+	for _, u := range defs {
+		if err := u.validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: user stratagem %q skipped: %v\n", u.Key, err)
+			continue
+		}
+		if _, builtin := Stratagems[u.Key]; builtin && !userStratagems[u.Key] {
+			fmt.Fprintf(os.Stderr, "Warning: user stratagem %q skipped: a built-in stratagem has that name\n", u.Key)
+			continue
+		}
+		Stratagems[u.Key] = u.def()
+		userStratagems[u.Key] = true
+	}
+}
+
+// ParseSequence reads a primitive sequence written as "feel → name → ritual",
+// "feel -> name", "feel,name" or "feel name".
+func ParseSequence(text string) ([]string, error) {
+	text = strings.NewReplacer("→", " ", "->", " ", ",", " ").Replace(text)
+	seq := strings.Fields(text)
+	if len(seq) < minSequenceLen {
+		return nil, fmt.Errorf("a sequence needs at least %d primitives, got %d", minSequenceLen, len(seq))
+	}
+	for _, kind := range seq {
+		if !primitiveActions[kind] {
+			return nil, fmt.Errorf("%q is not a primitive", kind)
+		}
+	}
+	return seq, nil
+}
+
+// PromoteSequence saves seq as a user stratagem named key (default: the
+// primitives joined by dashes). history supplies its freestyle record.
+// Callers hold the state lock, which serializes writes to stratagems.json.
+func PromoteSequence(metacogDir string, history []HistoryEntry, text, key string) (UserStratagem, error) {
+	seq, err := ParseSequence(text)
+	if err != nil {
+		return UserStratagem{}, err
+	}
+	if key == "" {
+		key = strings.Join(seq, "-")
+	}
+	if !stratagemKeyPattern.MatchString(key) {
+		return UserStratagem{}, fmt.Errorf("invalid stratagem name %q: use lowercase letters, digits, '-' and '_'", key)
+	}
+	if _, exists := Stratagems[key]; exists && !userStratagems[key] {
+		return UserStratagem{}, fmt.Errorf("%q is a built-in stratagem. Choose another --name", key)
+	}
+
+	record := FindSequenceStats(MineFreestyleSequences(history), seq)
+	u := UserStratagem{
+		Key:        key,
+		Steps:      seq,
+		Productive: record.Productive,
+		Total:      record.Total,
+		PromotedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	existing, err := LoadUserStratagems(metacogDir)
+	if err != nil {
+		return UserStratagem{}, err
+	}
+	for _, other := range existing {
+		if other.Key == key {
+			return UserStratagem{}, fmt.Errorf("stratagem %q already promoted. Choose another --name", key)
+		}
+	}
+	if err := writePoolFile(metacogDir, userStratagemsFile, append(existing, u)); err != nil {
+		return UserStratagem{}, fmt.Errorf("cannot save user stratagems: %w", err)
+	}
+	Stratagems[key] = u.def()
+	userStratagems[key] = true
+	return u, nil
+}
+
+var promoteName string
+
+var stratagemPromoteCmd = &cobra.Command{
+	Use:   "promote <sequence>",
+	Short: "Save a freestyle primitive sequence as a stratagem",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		var output string
+		err := sm.SaveWithLock(func(s *State) error {
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			u, err := PromoteSequence(sm.dir, merged.History, strings.Join(args, " "), promoteName)
+			if err != nil {
+				return err
+			}
+			s.AddHistory(HistoryEntry{
+				Action: "stratagem",
+				Params: map[string]string{"name": u.Key, "event": "promoted", "sequence": strings.Join(u.Steps, " → ")},
+			})
+			record := "not yet seen in rated freestyle"
+			if u.Total > 0 {
+				record = fmt.Sprintf("productive in %d/%d rated freestyle bursts", u.Productive, u.Total)
+			}
+			output = fmt.Sprintf("Promoted %s to stratagem %q (%s).\nStart it with 'metacog stratagem start %s'.", strings.Join(u.Steps, " → "), u.Key, record, u.Key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+func init() {
+	stratagemPromoteCmd.Flags().StringVar(&promoteName, "name", "", "Stratagem name (default: the primitives joined by dashes)")
+	stratagemCmd.AddCommand(stratagemPromoteCmd)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func forgetUserStratagem(key string) {
+	delete(Stratagems, key)
+	delete(userStratagems, key)
+}
+
+func TestParseSequence(t *testing.T) {
+	for _, text := range []string{"feel → name → ritual", "feel -> name -> ritual", "feel,name,ritual", "feel name ritual"} {
+		seq, err := ParseSequence(text)
+		if err != nil || strings.Join(seq, " ") != "feel name ritual" {
+			t.Errorf("%q: got %v, %v", text, seq, err)
+		}
+	}
+	for _, text := range []string{"feel", "feel → dance"} {
+		if _, err := ParseSequence(text); err == nil {
+			t.Errorf("%q: expected error", text)
+		}
+	}
+}
+
+func TestPromoteSequence(t *testing.T) {
+	dir := t.TempDir()
+	defer forgetUserStratagem("feel-name")
+	s := NewState()
+	ratedBurst(s, "productive", "feel", "name")
+	ratedBurst(s, "unproductive", "feel", "name")
+
+	u, err := PromoteSequence(dir, s.History, "feel → name", "")
+	if err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	if u.Key != "feel-name" || u.Productive != 1 || u.Total != 2 {
+		t.Errorf("expected feel-name with its 1/2 record, got %+v", u)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "stratagems.json")); err != nil {
+		t.Errorf("expected stratagems.json: %v", err)
+	}
+
+	out, err := StartStratagem(s, "feel-name", false)
+	if err != nil {
+		t.Fatalf("start promoted stratagem: %v", err)
+	}
+	if !strings.Contains(out, "FEEL-NAME") {
+		t.Errorf("expected the promoted stratagem's steps, got:\n%s", out)
+	}
+
+	if _, err := PromoteSequence(dir, s.History, "feel name", ""); err == nil || !strings.Contains(err.Error(), "already promoted") {
+		t.Errorf("expected duplicate error, got %v", err)
+	}
+	if _, err := PromoteSequence(dir, s.History, "feel name", "pivot"); err == nil || !strings.Contains(err.Error(), "built-in") {
+		t.Errorf("expected built-in name to be refused, got %v", err)
+	}
+	if _, err := PromoteSequence(dir, s.History, "feel name", "Bad Name"); err == nil {
+		t.Error("expected invalid name to be refused")
+	}
+}
+
+func TestRegisterUserStratagems(t *testing.T) {
+	dir := t.TempDir()
+	defer forgetUserStratagem("loop")
+	writePoolFile(dir, userStratagemsFile, []UserStratagem{
+		{Key: "loop", Steps: []string{"feel", "ritual"}},
+		{Key: "pivot", Steps: []string{"feel", "name"}},
+		{Key: "empty"},
+		{Key: "dance", Steps: []string{"feel", "dance"}},
+		{Key: "Bad Key", Steps: []string{"feel", "name"}},
+	})
+
+	registerUserStratagems(dir)
+	for _, key := range []string{"empty", "dance", "Bad Key"} {
+		if _, ok := Stratagems[key]; ok {
+			forgetUserStratagem(key)
+			t.Errorf("invalid entry %q should be skipped", key)
+		}
+	}
+	if _, err := StartStratagem(NewState(), "empty", false); err == nil {
+		t.Error("a skipped entry should not start")
+	}
+	if def, ok := Stratagems["loop"]; !ok || len(def.Steps) != 2 || def.Steps[1].Kind != StepRitual {
+		t.Errorf("expected loop registered, got %+v", def)
+	}
+	if Stratagems["pivot"].Name != "THE PIVOT" {
+		t.Error("a user stratagem must not shadow a built-in one")
+	}
+}
+
+func TestLoadUserStratagemsCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(userStratagemsPath(dir), []byte("{not json"), 0644)
+	_, err := LoadUserStratagems(dir)
+	if err == nil || !strings.Contains(err.Error(), "user stratagems") || strings.Contains(err.Error(), "pool") {
+		t.Errorf("expected a user stratagems error, got %v", err)
+	}
+}
+
+func TestActiveStratagemMissingDefinition(t *testing.T) {
+	s := NewState()
+	s.Stratagem = &ActiveStratagem{Name: "gone", StepsCompleted: []string{}}
+
+	if _, err := AdvanceStratagem(s); err == nil || !strings.Contains(err.Error(), "stratagem abort") {
+		t.Errorf("expected an error pointing at abort, got %v", err)
+	}
+	if status := StratagemStatus(s); !strings.Contains(status, `"gone" is not defined`) {
+		t.Errorf("expected status to report the missing definition, got %q", status)
+	}
+	ValidatePrimitiveForStratagem(s, "feel")
+	if _, err := StartStratagem(s, "pivot", false); err == nil || !strings.Contains(err.Error(), `"gone" is active`) {
+		t.Errorf("expected the active-stratagem error, got %v", err)
+	}
+	if err := AbortStratagem(s); err != nil {
+		t.Errorf("abort should still work: %v", err)
+	}
+}
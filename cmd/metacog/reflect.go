@@ -17,7 +17,10 @@ func allStratagemNames() []string {
 	return names
 }
 
-func FormatReflection(s *State) string {
+// FormatReflection reports patterns in s.History. history is the full history
+// ending with s.History: outcome targets are indices into it, so abandonment,
+// freestyle sequences and revisions to archived outcomes are read from it.
+func FormatReflection(s *State, history []HistoryEntry) string {
 	if len(s.History) == 0 {
 		return "No history to reflect on."
 	}
@@ -130,6 +133,7 @@ func FormatReflection(s *State) string {
 	if len(neverCompleted) > 0 {
 		b.WriteString(fmt.Sprintf("  Never completed: %s\n", strings.Join(neverCompleted, ", ")))
 	}
+	b.WriteString(FormatAbandonment(history))
 
 	// Effectiveness section — only show if outcomes exist
 	type outcomeStats struct {
@@ -140,7 +144,8 @@ func FormatReflection(s *State) string {
 	for _, h := range s.History {
 		if h.Action == "outcome" {
 			name := h.Params["stratagem"]
-			if name == "" {
+			// Outcomes on aborted or abandoned runs are reported under abandonment
+			if name == "" || h.Params["status"] != "" {
 				continue
 			}
 			if outcomesByStratagem[name] == nil {
@@ -200,8 +205,19 @@ func FormatReflection(s *State) string {
 			overallRate := float64(totalProductive) / float64(totalOutcomes) * 100
 			b.WriteString(fmt.Sprintf("\n  Overall: %.0f%% productive (%d/%d)\n", overallRate, totalProductive, totalOutcomes))
 		}
+		revised := 0
+		for _, h := range history {
+			if h.Action == "outcome" && len(h.Amendments) > 0 {
+				revised++
+			}
+		}
+		if revised > 0 {
+			b.WriteString(fmt.Sprintf("  Revised since recorded: %d (see 'metacog outcome list')\n", revised))
+		}
 	}
 
+	b.WriteString(FormatFreestyleSequences(MineFreestyleSequences(history)))
+
 	totalSteps := 0
 	ritualCount := 0
 	for _, h := range s.History {
@@ -222,6 +238,160 @@ func FormatReflection(s *State) string {
 	return b.String()
 }
 
+// AbandonmentStats describes how often runs of one stratagem stopped before
+// their last step, whether aborted or replaced by another run.
+type AbandonmentStats struct {
+	Name    string
+	Ended   int
+	Stopped int
+	// AtStep counts stopped runs by the 1-based step they stopped at
+	AtStep       map[int]int
+	Productive   int
+	Unproductive int
+}
+
+func (a AbandonmentStats) Rate() float64 {
+	return float64(a.Stopped) / float64(a.Ended) * 100
+}
+
+// TypicalStep is the step runs most often stopped at; ties go to the earlier step.
+func (a AbandonmentStats) TypicalStep() int {
+	typical := 0
+	for step, n := range a.AtStep {
+		if n > a.AtStep[typical] || (n == a.AtStep[typical] && step < typical) {
+			typical = step
+		}
+	}
+	return typical
+}
+
+// StratagemAbandonment tallies finished runs per stratagem, for those with at
+// least one aborted or abandoned run, highest abandonment rate first.
+func StratagemAbandonment(history []HistoryEntry) []AbandonmentStats {
+	byName := map[string]*AbandonmentStats{}
+	for _, t := range OutcomeTargets(history) {
+		if t.Kind != TargetStratagem {
+			continue
+		}
+		a := byName[t.Name]
+		if a == nil {
+			a = &AbandonmentStats{Name: t.Name, AtStep: map[int]int{}}
+			byName[t.Name] = a
+		}
+		a.Ended++
+		if t.Status == "completed" {
+			continue
+		}
+		a.Stopped++
+		a.AtStep[t.StepAt+1]++
+		if t.Outcome >= 0 {
+			if history[t.Outcome].Params["result"] == "productive" {
+				a.Productive++
+			} else {
+				a.Unproductive++
+			}
+		}
+	}
+	var stats []AbandonmentStats
+	for _, a := range byName {
+		if a.Stopped > 0 {
+			stats = append(stats, *a)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Rate() != stats[j].Rate() {
+			return stats[i].Rate() > stats[j].Rate()
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+func FormatAbandonment(history []HistoryEntry) string {
+	stats := StratagemAbandonment(history)
+	if len(stats) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nAbandonment (aborted or replaced before the last step):\n")
+	for _, a := range stats {
+		step := fmt.Sprintf("%d", a.TypicalStep())
+		if def, err := lookupStratagem(a.Name); err == nil {
+			step += fmt.Sprintf("/%d", len(def.Steps))
+		}
+		line := fmt.Sprintf("  %s: %.0f%% (%d/%d runs), typically at step %s", a.Name, a.Rate(), a.Stopped, a.Ended, step)
+		if rated := a.Productive + a.Unproductive; rated > 0 {
+			line += fmt.Sprintf("; stopping was productive %d/%d", a.Productive, rated)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// FormatCommitmentAudit reports commitment verdicts by stratagem across
+// history, the full history ending with s.History. Commitments recorded
+// before IDs can't be resolved and are left out.
+func FormatCommitmentAudit(s *State, history []HistoryEntry) string {
+	var records []CommitmentRecord
+	for _, r := range CollectCommitments(s, history) {
+		if r.ID != "" {
+			records = append(records, r)
+		}
+	}
+	if len(records) == 0 {
+		return ""
+	}
+
+	type verdictCounts struct {
+		held, falsified, unresolved int
+	}
+	total := verdictCounts{}
+	byStratagem := map[string]*verdictCounts{}
+	for _, r := range records {
+		name := r.Stratagem
+		if name == "" {
+			name = "freestyle"
+		}
+		if byStratagem[name] == nil {
+			byStratagem[name] = &verdictCounts{}
+		}
+		c := byStratagem[name]
+		switch r.Verdict {
+		case VerdictHeld:
+			c.held++
+			total.held++
+		case VerdictFalsified:
+			c.falsified++
+			total.falsified++
+		default:
+			c.unresolved++
+			total.unresolved++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\nCommitments:\n")
+	if resolved := total.held + total.falsified; resolved > 0 {
+		rate := float64(total.held) / float64(resolved) * 100
+		b.WriteString(fmt.Sprintf("  Kept: %.0f%% (%d held, %d falsified)\n", rate, total.held, total.falsified))
+	}
+	if total.unresolved > 0 {
+		b.WriteString(fmt.Sprintf("  Unresolved: %d\n", total.unresolved))
+	}
+
+	names := make([]string, 0, len(byStratagem))
+	for name := range byStratagem {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteString("  By stratagem:\n")
+	for _, name := range names {
+		c := byStratagem[name]
+		b.WriteString(fmt.Sprintf("    %s: %d held, %d falsified, %d unresolved\n", name, c.held, c.falsified, c.unresolved))
+	}
+	return b.String()
+}
+
 func FormatRecentInsights(entries []JournalEntry, n int) string {
 	if len(entries) == 0 {
 		return ""
@@ -232,10 +402,13 @@ func FormatRecentInsights(entries []JournalEntry, n int) string {
 	var b strings.Builder
 	b.WriteString("\nRecent insights:\n")
 	for _, e := range entries {
-		b.WriteString(fmt.Sprintf("  [%s] %s", e.Timestamp, e.Insight))
+		b.WriteString(fmt.Sprintf("  [%s] %s", e.Timestamp, indentInsight(e.Insight, "    ")))
 		if len(e.Tags) > 0 {
 			b.WriteString(fmt.Sprintf(" [%s]", strings.Join(e.Tags, ", ")))
 		}
+		if ctx := e.Context.String(); ctx != "" {
+			b.WriteString(fmt.Sprintf(" — %s", ctx))
+		}
 		b.WriteString("\n")
 	}
 	return b.String()
@@ -424,7 +597,8 @@ func FormatAdvisories(s *State, journal []JournalEntry) string {
 	for _, h := range s.History {
 		if h.Action == "outcome" {
 			name := h.Params["stratagem"]
-			if name == "" {
+			// Outcomes on aborted or abandoned runs are reported under abandonment
+			if name == "" || h.Params["status"] != "" {
 				continue
 			}
 			if outcomesByName[name] == nil {
@@ -474,15 +648,7 @@ func FormatAdvisories(s *State, journal []JournalEntry) string {
 
 	// 4. Over-reliance — single identity >50% of last 20 becomes, or single substrate >50% of last 20 drugs
 	checkOverReliance := func(action, paramKey, label string) {
-		var recent []string
-		for i := len(s.History) - 1; i >= 0 && len(recent) < 20; i-- {
-			if s.History[i].Action == action {
-				val := s.History[i].Params[paramKey]
-				if val != "" {
-					recent = append(recent, val)
-				}
-			}
-		}
+		recent := recentParamValues(s, action, paramKey, OverRelianceWindow)
 		if len(recent) < 4 {
 			return
 		}
@@ -554,7 +720,12 @@ var reflectCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		output := FormatReflection(s)
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		output := FormatReflection(s, merged.History)
+		output += FormatCommitmentAudit(s, merged.History)
 
 		journal, err := sm.LoadJournal()
 		if err == nil && len(journal) > 0 {
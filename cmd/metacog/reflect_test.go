@@ -8,7 +8,7 @@ import (
 
 func TestReflectEmpty(t *testing.T) {
 	s := NewState()
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "No history") {
 		t.Error("empty history should say so")
 	}
@@ -24,7 +24,7 @@ func TestReflectPrimitiveCounts(t *testing.T) {
 	}
 	s.AddHistory(HistoryEntry{Action: "ritual", Params: map[string]string{"threshold": "test"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "become: 5") {
 		t.Errorf("expected become: 5 in output:\n%s", output)
 	}
@@ -46,7 +46,7 @@ func TestReflectTopIdentities(t *testing.T) {
 	}
 	s.AddHistory(HistoryEntry{Action: "become", Params: map[string]string{"name": "Doepfer"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "Ada") {
 		t.Errorf("expected Ada in top identities:\n%s", output)
 	}
@@ -60,7 +60,7 @@ func TestReflectStratagemUsage(t *testing.T) {
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "stack", "event": "completed"}})
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "mirror", "event": "started"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "pivot: 3") {
 		t.Errorf("expected 'pivot: 3' in output:\n%s", output)
 	}
@@ -81,7 +81,7 @@ func TestReflectStratagemUsageIncludesZen(t *testing.T) {
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "zen", "event": "completed"}})
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "zen: 2") {
 		t.Errorf("expected 'zen: 2' in completions output:\n%s", output)
 	}
@@ -91,7 +91,7 @@ func TestReflectStratagemUsageZenInNeverCompleted(t *testing.T) {
 	s := NewState()
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	// zen has never been completed, so it should appear in the never-completed list
 	neverIdx := strings.Index(output, "Never completed:")
 	if neverIdx == -1 {
@@ -111,7 +111,7 @@ func TestReflectEffectivenessUnmeasuredIncludesZen(t *testing.T) {
 	// zen completed with no outcome -> should be 'unmeasured'
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "zen", "event": "completed"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "zen: unmeasured") {
 		t.Errorf("expected 'zen: unmeasured' in effectiveness section:\n%s", output)
 	}
@@ -122,7 +122,7 @@ func TestReflectRitualStepAverage(t *testing.T) {
 	s.AddHistory(HistoryEntry{Action: "ritual", Params: map[string]string{"steps": "a; b; c"}})
 	s.AddHistory(HistoryEntry{Action: "ritual", Params: map[string]string{"steps": "a; b; c; d; e"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	if !strings.Contains(output, "4.0") {
 		t.Errorf("expected average 4.0 steps in output:\n%s", output)
 	}
@@ -146,7 +146,7 @@ func TestReflectEffectiveness(t *testing.T) {
 	// mirror: completed but no outcome = unmeasured
 	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "mirror", "event": "completed"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 
 	if !strings.Contains(output, "self-reported") {
 		t.Errorf("expected 'self-reported' framing in output:\n%s", output)
@@ -168,11 +168,95 @@ func TestReflectEffectiveness(t *testing.T) {
 	}
 }
 
+func TestReflectEffectivenessUsesAmendedVerdict(t *testing.T) {
+	s := NewState()
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
+	RecordOutcome(s, "productive", "")
+	if _, err := AmendOutcomeAt(s, s.History, "2", "unproductive", "", false, "wore off"); err != nil {
+		t.Fatalf("amend: %v", err)
+	}
+
+	output := FormatReflection(s, s.History)
+	if !strings.Contains(output, "pivot: 0% productive (0/1)") {
+		t.Errorf("expected the amended verdict to count:\n%s", output)
+	}
+	if !strings.Contains(output, "Revised since recorded: 1") {
+		t.Errorf("expected the revision to be noted:\n%s", output)
+	}
+}
+
+func TestReflectAbandonment(t *testing.T) {
+	s := NewState()
+	stop := func(name, status string, step int) {
+		s.AddHistory(HistoryEntry{Action: "stratagem", Status: status, StepAt: step, Params: map[string]string{"name": name}})
+	}
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
+	stop("pivot", "aborted", 1)
+	stop("pivot", "abandoned", 1)
+	stop("pivot", "aborted", 3)
+	s.AddHistory(HistoryEntry{Action: "outcome", Params: map[string]string{"result": "productive", "stratagem": "pivot", "target": "4", "status": "abandoned"}})
+	stop("mirror", "aborted", 0)
+
+	output := FormatReflection(s, s.History)
+	for _, want := range []string{
+		"mirror: 100% (1/1 runs), typically at step 1/",
+		"pivot: 75% (3/4 runs), typically at step 2/5; stopping was productive 1/1",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Effectiveness") {
+		t.Errorf("outcomes on stopped runs should not count toward effectiveness:\n%s", output)
+	}
+	if strings.Index(output, "mirror: 100%") > strings.Index(output, "pivot: 75%") {
+		t.Error("expected highest abandonment rate first")
+	}
+}
+
+func TestReflectAbandonmentWithArchive(t *testing.T) {
+	archived := []HistoryEntry{
+		{Action: "become", Params: map[string]string{"name": "Ada"}},
+		{Action: "become", Params: map[string]string{"name": "Eno"}},
+	}
+	s := NewState()
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}})
+	s.AddHistory(HistoryEntry{Action: "stratagem", Status: "aborted", StepAt: 1, Params: map[string]string{"name": "pivot"}})
+	history := append(append([]HistoryEntry{}, archived...), s.History...)
+	if _, err := RecordOutcomeFor(s, history, "productive", "", TargetLastStratagem); err != nil {
+		t.Fatal(err)
+	}
+	history = append(append([]HistoryEntry{}, archived...), s.History...)
+
+	output := FormatReflection(s, history)
+	if !strings.Contains(output, "pivot: 50% (1/2 runs), typically at step 2/5; stopping was productive 1/1") {
+		t.Errorf("the outcome's target indexes the full history:\n%s", output)
+	}
+}
+
+func TestReflectFreestyleSequencesWithArchive(t *testing.T) {
+	archived := []HistoryEntry{{Action: "inspire"}, {Action: "inspire"}, {Action: "inspire"}}
+	s := NewState()
+	full := func() []HistoryEntry { return append(append([]HistoryEntry{}, archived...), s.History...) }
+	for i := 0; i < 3; i++ {
+		applyFeel(s, "chest", "warm", "o", "")
+		applyName(s, "knot", "tight", "")
+		if _, err := RecordOutcomeFor(s, full(), "productive", "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := FormatReflection(s, full())
+	if !strings.Contains(output, "feel → name: 100% productive (3/3)") {
+		t.Errorf("rated bursts after archiving began should be mined:\n%s", output)
+	}
+}
+
 func TestReflectNoOutcomes(t *testing.T) {
 	s := NewState()
 	s.AddHistory(HistoryEntry{Action: "become", Params: map[string]string{"name": "test"}})
 
-	output := FormatReflection(s)
+	output := FormatReflection(s, s.History)
 	// Should not contain effectiveness section when no outcomes exist
 	if strings.Contains(output, "effectiveness") {
 		t.Errorf("should not show effectiveness with no outcomes:\n%s", output)
@@ -59,9 +59,12 @@ The speaker is unchanged. The pitch is not. Stay in the new register until the n
 }
 
 func applyRegister(s *State, from, to, rationale string) {
+	CloseConstraintsOn(s, BoundaryRegister)
+	id := OpenConstraint(s, "register", fmt.Sprintf("%s -> %s", from, to))
 	s.AddHistory(HistoryEntry{
 		Action: "register",
 		Params: map[string]string{
+			"id":        id,
 			"from":      from,
 			"to":        to,
 			"rationale": rationale,
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Answers to the goal-met question at 'session end'.
+const (
+	GoalMetYes    = "yes"
+	GoalMetPartly = "partly"
+	GoalMetNo     = "no"
+)
+
+// SessionClose holds the closing answers for a session. All are optional.
+type SessionClose struct {
+	GoalMet string
+	Shifted string
+	Keep    string
+}
+
+func (c SessionClose) empty() bool {
+	return c.GoalMet == "" && c.Shifted == "" && c.Keep == ""
+}
+
+func (c SessionClose) validate() error {
+	switch c.GoalMet {
+	case "", GoalMetYes, GoalMetPartly, GoalMetNo:
+		return nil
+	}
+	return fmt.Errorf("--goal-met must be %s, %s or %s, got %q", GoalMetYes, GoalMetPartly, GoalMetNo, c.GoalMet)
+}
+
+func (c SessionClose) addParams(params map[string]string) {
+	if c.GoalMet != "" {
+		params["goal_met"] = c.GoalMet
+	}
+	if c.Shifted != "" {
+		params["shifted"] = c.Shifted
+	}
+	if c.Keep != "" {
+		params["keep"] = c.Keep
+	}
+}
+
+func sessionCloseFromParams(params map[string]string) SessionClose {
+	return SessionClose{GoalMet: params["goal_met"], Shifted: params["shifted"], Keep: params["keep"]}
+}
+
+func (c SessionClose) String() string {
+	var b strings.Builder
+	if c.GoalMet != "" {
+		b.WriteString(fmt.Sprintf("Goal met: %s\n", c.GoalMet))
+	}
+	if c.Shifted != "" {
+		b.WriteString(fmt.Sprintf("What shifted: %s\n", c.Shifted))
+	}
+	if c.Keep != "" {
+		b.WriteString(fmt.Sprintf("What to keep: %s\n", c.Keep))
+	}
+	return b.String()
+}
+
+func setSessionGoal(s *State, name, goal string) {
+	if goal == "" {
+		return
+	}
+	if s.SessionGoals == nil {
+		s.SessionGoals = map[string]string{}
+	}
+	s.SessionGoals[name] = goal
+}
+
+// recordSessionGoal attaches goal to the session just started, in state and
+// on its started event.
+func recordSessionGoal(s *State, goal string) {
+	goal = strings.TrimSpace(goal)
+	if goal == "" {
+		return
+	}
+	setSessionGoal(s, s.Session, goal)
+	s.History[len(s.History)-1].Params["goal"] = goal
+}
+
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// promptSessionClose asks the closing questions interactively. Blank answers
+// are left empty.
+func promptSessionClose(in io.Reader, out io.Writer, goal string) SessionClose {
+	r := bufio.NewReader(in)
+	ask := func(question string) string {
+		fmt.Fprint(out, question)
+		line, _ := r.ReadString('\n')
+		return strings.TrimSpace(line)
+	}
+	var c SessionClose
+	if goal != "" {
+		fmt.Fprintf(out, "Goal: %s\n", goal)
+		for {
+			c.GoalMet = strings.ToLower(ask("Goal met? (yes/partly/no, blank to skip): "))
+			if c.validate() == nil {
+				break
+			}
+		}
+	}
+	c.Shifted = ask("What shifted? ")
+	c.Keep = ask("What to keep? ")
+	return c
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// retrospectivePath is $METACOG_HOME/sessions/NAME.md, with characters that
+// aren't safe in a file name replaced by dashes.
+func retrospectivePath(sm *StateManager, name string) string {
+	file := strings.Trim(unsafeFileChars.ReplaceAllString(name, "-"), "-.")
+	if file == "" {
+		file = "session"
+	}
+	return filepath.Join(sm.dir, "sessions", file+".md")
+}
+
+func FormatRetrospective(sum *SessionSummary) string {
+	var b strings.Builder
+	info := sum.Info
+	b.WriteString(fmt.Sprintf("# Session: %s\n\n", info.Name))
+	if info.Goal != "" {
+		b.WriteString(fmt.Sprintf("Goal: %s\n", info.Goal))
+	}
+	b.WriteString(info.Close.String())
+	if info.Goal != "" || !info.Close.empty() {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Summary\n\n")
+	if info.Parent != "" {
+		b.WriteString(fmt.Sprintf("Within: %s\n", info.Parent))
+	}
+	if len(info.Sittings) > 0 {
+		b.WriteString(fmt.Sprintf("Started: %s\n", info.Sittings[0][0]))
+		if end := info.Sittings[len(info.Sittings)-1][1]; end != "" {
+			b.WriteString(fmt.Sprintf("Ended: %s\n", end))
+		}
+	}
+	b.WriteString(fmt.Sprintf("Duration: %s over %d sittings\n", sum.Duration.Round(time.Minute), len(info.Sittings)))
+	if len(sum.Primitives) > 0 {
+		b.WriteString(fmt.Sprintf("Primitives: %s\n", formatCounts(sum.Primitives)))
+	}
+	if len(sum.StratagemsCompleted) > 0 {
+		b.WriteString(fmt.Sprintf("Stratagems completed: %s\n", strings.Join(sum.StratagemsCompleted, ", ")))
+	}
+	if len(sum.SubSessions) > 0 {
+		b.WriteString(fmt.Sprintf("Sub-sessions: %s\n", strings.Join(sum.SubSessions, ", ")))
+	}
+
+	var outcomes []HistoryEntry
+	var steps []string
+	for _, h := range sum.Entries {
+		if h.Action == "outcome" {
+			outcomes = append(outcomes, h)
+		}
+		if primitiveActions[h.Action] || h.Action == "stratagem" {
+			steps = append(steps, fmt.Sprintf("- %s %s%s", h.Timestamp, h.Action, retroDetail(h)))
+		}
+	}
+	if len(steps) > 0 {
+		b.WriteString("\n## History\n\n")
+		b.WriteString(strings.Join(steps, "\n") + "\n")
+	}
+	if len(outcomes) > 0 {
+		b.WriteString("\n## Outcomes\n\n")
+		for _, h := range outcomes {
+			line := fmt.Sprintf("- %s %s", h.Timestamp, h.Params["result"])
+			if name := h.Params["stratagem"]; name != "" {
+				line += fmt.Sprintf(" (%s)", name)
+			}
+			if shift := h.Params["shift"]; shift != "" {
+				line += ": " + shift
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	if len(sum.Journal) > 0 {
+		b.WriteString("\n## Journal\n\n")
+		for _, e := range sum.Journal {
+			b.WriteString(fmt.Sprintf("- [%s] %s\n", e.Timestamp, indentInsight(e.Insight, "  ")))
+		}
+	}
+	return b.String()
+}
+
+// retroDetail names what a history entry acted on, for the retrospective's history list.
+func retroDetail(h HistoryEntry) string {
+	if h.Action == "stratagem" {
+		return fmt.Sprintf(" %s %s", h.Params["name"], h.Params["event"])
+	}
+	for _, key := range []string{"name", "substance", "sigil", "quality"} {
+		if v := h.Params[key]; v != "" {
+			return ": " + v
+		}
+	}
+	return ""
+}
+
+// writeRetrospective regenerates the retrospective for name from the full
+// history and journal, so resuming and ending again keeps it complete.
+func writeRetrospective(sm *StateManager, name string) (string, error) {
+	s, err := sm.Load()
+	if err != nil {
+		return "", err
+	}
+	merged, err := mergeArchivedHistory(sm, s)
+	if err != nil {
+		return "", err
+	}
+	journal, err := sm.LoadJournal()
+	if err != nil {
+		return "", err
+	}
+	sum, err := SummarizeSession(merged.History, journal, name, time.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+
+	path := retrospectivePath(sm, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("cannot create sessions directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(FormatRetrospective(sum)), 0644); err != nil {
+		return "", fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	return path, nil
+}
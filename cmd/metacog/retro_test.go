@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionGoalLifecycle(t *testing.T) {
+	s := NewState()
+	StartSession(s, "api")
+	recordSessionGoal(s, "  find the seam  ")
+	if s.SessionGoals["api"] != "find the seam" {
+		t.Errorf("expected goal in state, got %v", s.SessionGoals)
+	}
+	if s.History[len(s.History)-1].Params["goal"] != "find the seam" {
+		t.Error("expected goal on the started event")
+	}
+
+	if err := CloseSession(s, SessionClose{GoalMet: "maybe"}); err == nil {
+		t.Error("expected error for an invalid goal-met answer")
+	}
+	if s.Session != "api" {
+		t.Fatal("a rejected close should leave the session open")
+	}
+	if err := CloseSession(s, SessionClose{GoalMet: GoalMetPartly, Shifted: "scope", Keep: "the map"}); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, ok := s.SessionGoals["api"]; ok {
+		t.Error("goal should be cleared when the session ends")
+	}
+
+	info, _ := findSessionInfo(SessionInfos(s.History), "api")
+	if info.Goal != "find the seam" || info.Close.GoalMet != GoalMetPartly || info.Close.Keep != "the map" {
+		t.Errorf("expected goal and close answers from history, got %+v", info)
+	}
+
+	if err := ResumeSession(s, s.History, "api"); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if s.SessionGoals["api"] != "find the seam" {
+		t.Error("resuming should restore the goal")
+	}
+}
+
+func TestPromptSessionClose(t *testing.T) {
+	var out strings.Builder
+	in := strings.NewReader("sorta\nYes\nthe frame\n\n")
+	c := promptSessionClose(in, &out, "ship it")
+	if c.GoalMet != GoalMetYes || c.Shifted != "the frame" || c.Keep != "" {
+		t.Errorf("unexpected answers %+v", c)
+	}
+	if strings.Count(out.String(), "Goal met?") != 2 {
+		t.Errorf("an invalid answer should be asked again:\n%s", out.String())
+	}
+
+	out.Reset()
+	c = promptSessionClose(strings.NewReader("x\ny\n"), &out, "")
+	if strings.Contains(out.String(), "Goal met?") || c.Shifted != "x" || c.Keep != "y" {
+		t.Errorf("without a goal only shifted/keep are asked, got %+v\n%s", c, out.String())
+	}
+}
+
+func TestRetrospectivePath(t *testing.T) {
+	sm := NewStateManager("/home/m")
+	cases := map[string]string{
+		"api-redesign":  "/home/m/sessions/api-redesign.md",
+		"deep dive/two": "/home/m/sessions/deep-dive-two.md",
+		"../..":         "/home/m/sessions/session.md",
+	}
+	for name, want := range cases {
+		if got := retrospectivePath(sm, name); got != want {
+			t.Errorf("%q: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestWriteRetrospective(t *testing.T) {
+	dir := t.TempDir()
+	sm := NewStateManager(dir)
+	sm.SaveWithLock(func(s *State) error {
+		StartSession(s, "deep")
+		recordSessionGoal(s, "map the territory")
+		applyBecome(s, "Ada", "logic", "lab")
+		RecordOutcome(s, "productive", "saw the loop")
+		return CloseSession(s, SessionClose{GoalMet: GoalMetYes, Keep: "loops"})
+	})
+	sm.AppendJournal(JournalEntry{ID: "j1", Timestamp: time.Now().UTC().Format(time.RFC3339), Insight: "loops everywhere", Session: "deep"})
+
+	path, err := writeRetrospective(sm, "deep")
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if path != filepath.Join(dir, "sessions", "deep.md") {
+		t.Errorf("unexpected path %s", path)
+	}
+	data, _ := os.ReadFile(path)
+	retro := string(data)
+	for _, want := range []string{"# Session: deep", "Goal: map the territory", "Goal met: yes", "What to keep: loops",
+		"Primitives: become 1", "become: Ada", "productive (freestyle): saw the loop", "loops everywhere"} {
+		if !strings.Contains(retro, want) {
+			t.Errorf("retrospective should contain %q:\n%s", want, retro)
+		}
+	}
+}
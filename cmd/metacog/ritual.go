@@ -67,4 +67,5 @@ func applyRitual(s *State, threshold string, steps []string, result string) {
 			"result":    result,
 		},
 	})
+	CloseConstraintsOn(s, BoundaryRitual)
 }
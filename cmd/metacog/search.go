@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Match strengths for a query term against a stance field.
+const (
+	matchTypo      = 1
+	matchSubstring = 2
+)
+
+type StanceMatch struct {
+	Pool   string
+	Stance Stance
+	Score  int
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// matchTerm scores one lowercased term against lowercased text: a substring
+// hit beats a near-miss spelling of one of its words.
+func matchTerm(term, text string) int {
+	if strings.Contains(text, term) {
+		return matchSubstring
+	}
+	allowed := 0
+	switch {
+	case len(term) >= 7:
+		allowed = 2
+	case len(term) >= 4:
+		allowed = 1
+	}
+	if allowed > 0 {
+		for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !('a' <= r && r <= 'z' || '0' <= r && r <= '9') }) {
+			if levenshtein(term, word) <= allowed {
+				return matchTypo
+			}
+		}
+	}
+	return 0
+}
+
+// SearchStances returns stances where every query term matches one of the
+// searched fields, best matches first.
+func SearchStances(pools map[string]StancePool, query string, fields []string) ([]StanceMatch, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+	if len(fields) == 0 {
+		fields = []string{"who", "where", "lens"}
+	}
+	for _, f := range fields {
+		if f != "who" && f != "where" && f != "lens" {
+			return nil, fmt.Errorf("unknown field %q. Search who, where or lens", f)
+		}
+	}
+
+	var matches []StanceMatch
+	for _, name := range ListPoolNames(pools) {
+		for _, st := range pools[name].Stances {
+			values := map[string]string{
+				"who":   strings.ToLower(st.Who),
+				"where": strings.ToLower(st.Where),
+				"lens":  strings.ToLower(st.Lens),
+			}
+			total := 0
+			for _, term := range terms {
+				best := 0
+				for _, f := range fields {
+					best = max(best, matchTerm(term, values[f]))
+				}
+				if best == 0 {
+					total = 0
+					break
+				}
+				total += best
+			}
+			if total > 0 {
+				matches = append(matches, StanceMatch{name, st, total})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches, nil
+}
+
+func FormatStanceMatches(matches []StanceMatch, limit int) string {
+	if len(matches) == 0 {
+		return "No matching stances."
+	}
+	shown := matches
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	lines := make([]string, 0, len(shown)+1)
+	for _, m := range shown {
+		lines = append(lines, fmt.Sprintf("[%s] %s — %s — %s", m.Pool, m.Stance.Who, m.Stance.Where, m.Stance.Lens))
+	}
+	if len(shown) < len(matches) {
+		lines = append(lines, fmt.Sprintf("... %d more (raise --limit)", len(matches)-len(shown)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	searchPools  []string
+	searchFields []string
+	searchLimit  int
+)
+
+// Search is hidden from help: browsing the pools undercuts finding dense
+// coordinates yourself (see GEMINI.md), so it is only for humans who opt in.
+var inspireSearchCmd = &cobra.Command{
+	Use:    "search [query]",
+	Short:  "Fuzzy-search stances by who, where and lens (opt-in via config)",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		cfg, err := sm.LoadConfig()
+		if err != nil {
+			return err
+		}
+		if !cfg.Inspire.SearchEnabled {
+			return fmt.Errorf("stance search is disabled. To opt in, set {\"inspire\": {\"search_enabled\": true}} in %s", sm.configPath)
+		}
+		pools, err := LoadStancePoolsWithUser(sm.dir)
+		if err != nil {
+			return err
+		}
+		pools, err = FilterPools(pools, searchPools, nil)
+		if err != nil {
+			return err
+		}
+		matches, err := SearchStances(pools, strings.Join(args, " "), searchFields)
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, FormatStanceMatches(matches, searchLimit), nil))
+		return nil
+	},
+}
+
+func init() {
+	inspireSearchCmd.Flags().StringSliceVar(&searchPools, "pool", nil, "Only search these pools (repeatable or comma-separated)")
+	inspireSearchCmd.Flags().StringSliceVar(&searchFields, "field", nil, "Only search these fields: who, where, lens (default all)")
+	inspireSearchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Show at most N matches (0 for all)")
+	inspireCmd.AddCommand(inspireSearchCmd)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func searchTestPools() map[string]StancePool {
+	return map[string]StancePool{
+		"math":   {Name: "math", Stances: []Stance{{Who: "Emmy Noether", Where: "Göttingen", Lens: "symmetry and conservation"}, {Who: "Poincaré", Where: "Analysis Situs", Lens: "topology of phase space"}}},
+		"poetry": {Name: "poetry", Stances: []Stance{{Who: "Anne Carson", Where: "Eros the Bittersweet", Lens: "topology of desire"}}},
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	if d := levenshtein("topology", "topolgy"); d != 1 {
+		t.Errorf("expected 1, got %d", d)
+	}
+	if d := levenshtein("", "abc"); d != 3 {
+		t.Errorf("expected 3, got %d", d)
+	}
+}
+
+func TestSearchStancesSubstringAndTypo(t *testing.T) {
+	matches, err := SearchStances(searchTestPools(), "topology", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 topology matches, got %+v", matches)
+	}
+	matches, _ = SearchStances(searchTestPools(), "topolgy desire", nil)
+	if len(matches) != 1 || matches[0].Stance.Who != "Anne Carson" {
+		t.Errorf("expected typo-tolerant match on Carson only, got %+v", matches)
+	}
+	matches, _ = SearchStances(searchTestPools(), "top", nil)
+	if len(matches) != 2 {
+		t.Errorf("expected substring match on short term, got %+v", matches)
+	}
+	matches, _ = SearchStances(searchTestPools(), "tpo", nil)
+	if len(matches) != 0 {
+		t.Errorf("short terms should not be fuzzy, got %+v", matches)
+	}
+}
+
+func TestSearchStancesRanking(t *testing.T) {
+	matches, _ := SearchStances(searchTestPools(), "noether symetry", nil)
+	if len(matches) != 1 {
+		t.Fatalf("expected one match, got %+v", matches)
+	}
+	exact, _ := SearchStances(searchTestPools(), "noether symmetry", nil)
+	if exact[0].Score <= matches[0].Score {
+		t.Errorf("exact match should outscore a typo: %d vs %d", exact[0].Score, matches[0].Score)
+	}
+}
+
+func TestSearchStancesFields(t *testing.T) {
+	matches, _ := SearchStances(searchTestPools(), "carson", []string{"lens"})
+	if len(matches) != 0 {
+		t.Errorf("lens-only search should not match who, got %+v", matches)
+	}
+	if _, err := SearchStances(searchTestPools(), "x", []string{"bogus"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := SearchStances(searchTestPools(), "  ", nil); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestFormatStanceMatchesLimit(t *testing.T) {
+	matches, _ := SearchStances(searchTestPools(), "topology", nil)
+	out := FormatStanceMatches(matches, 1)
+	if !strings.Contains(out, "... 1 more") {
+		t.Errorf("expected truncation note, got %q", out)
+	}
+	if FormatStanceMatches(nil, 0) != "No matching stances." {
+		t.Error("unexpected empty output")
+	}
+}
+
+func TestSearchDisabledByDefault(t *testing.T) {
+	dir := t.TempDir()
+	cfg, _ := NewStateManager(dir).LoadConfig()
+	if cfg.Inspire.SearchEnabled {
+		t.Error("search must be disabled without config")
+	}
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"inspire":{"search_enabled":true}}`), 0644)
+	cfg, _ = NewStateManager(dir).LoadConfig()
+	if !cfg.Inspire.SearchEnabled {
+		t.Error("expected search enabled from config")
+	}
+	if !inspireSearchCmd.Hidden {
+		t.Error("search should stay out of help output")
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Freestyle sequences are mined as contiguous n-grams of primitive kinds, at
+// these lengths, from bursts that received an outcome.
+const (
+	minSequenceLen = 2
+	maxSequenceLen = 4
+)
+
+// SequenceStats tallies the rated freestyle bursts a primitive sequence occurred in.
+type SequenceStats struct {
+	Sequence   []string
+	Productive int
+	Total      int
+}
+
+func (q SequenceStats) String() string {
+	return strings.Join(q.Sequence, " → ")
+}
+
+func (q SequenceStats) Rate() float64 {
+	return float64(q.Productive) / float64(q.Total) * 100
+}
+
+// MineFreestyleSequences counts each n-gram once per rated burst. A sequence
+// is left out when a longer one containing it has the same record, since it
+// says nothing the longer one doesn't. Highest productive rate first.
+func MineFreestyleSequences(history []HistoryEntry) []SequenceStats {
+	byKey := map[string]*SequenceStats{}
+	for _, t := range OutcomeTargets(history) {
+		if t.Kind != TargetFreestyle || t.Outcome < 0 {
+			continue
+		}
+		productive := history[t.Outcome].Params["result"] == "productive"
+		actions := make([]string, len(t.Primitives))
+		for i, idx := range t.Primitives {
+			actions[i] = history[idx].Action
+		}
+		seen := map[string]bool{}
+		for n := minSequenceLen; n <= maxSequenceLen; n++ {
+			for i := 0; i+n <= len(actions); i++ {
+				seq := actions[i : i+n]
+				key := strings.Join(seq, " ")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				q := byKey[key]
+				if q == nil {
+					q = &SequenceStats{Sequence: append([]string(nil), seq...)}
+					byKey[key] = q
+				}
+				q.Total++
+				if productive {
+					q.Productive++
+				}
+			}
+		}
+	}
+
+	var stats []SequenceStats
+	for key, q := range byKey {
+		subsumed := false
+		for other, o := range byKey {
+			if len(o.Sequence) > len(q.Sequence) && o.Total == q.Total && o.Productive == q.Productive &&
+				strings.Contains(" "+other+" ", " "+key+" ") {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			stats = append(stats, *q)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.Rate() != b.Rate() {
+			return a.Rate() > b.Rate()
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if len(a.Sequence) != len(b.Sequence) {
+			return len(a.Sequence) > len(b.Sequence)
+		}
+		return a.String() < b.String()
+	})
+	return stats
+}
+
+// FindSequenceStats returns the record for seq, or a zero record if it never
+// occurred in a rated burst.
+func FindSequenceStats(stats []SequenceStats, seq []string) SequenceStats {
+	key := strings.Join(seq, " → ")
+	for _, q := range stats {
+		if q.String() == key {
+			return q
+		}
+	}
+	return SequenceStats{Sequence: seq}
+}
+
+// FormatFreestyleSequences reports recurring sequences (seen in 2+ rated
+// bursts): the most productive, then the least. Empty when none recur.
+func FormatFreestyleSequences(stats []SequenceStats) string {
+	var best, worst []SequenceStats
+	for _, q := range stats {
+		if q.Total < 2 {
+			continue
+		}
+		if q.Rate() >= 50 {
+			best = append(best, q)
+		} else {
+			worst = append(worst, q)
+		}
+	}
+	if len(best) == 0 && len(worst) == 0 {
+		return ""
+	}
+	if len(best) > 5 {
+		best = best[:5]
+	}
+	// worst is highest rate first; the least productive are at the end
+	if len(worst) > 3 {
+		worst = worst[len(worst)-3:]
+	}
+
+	var b strings.Builder
+	b.WriteString("\nFreestyle sequences (recurring before rated bursts):\n")
+	line := func(q SequenceStats) {
+		b.WriteString(fmt.Sprintf("  %s: %.0f%% productive (%d/%d)\n", q, q.Rate(), q.Productive, q.Total))
+	}
+	for _, q := range best {
+		line(q)
+	}
+	if len(worst) > 0 {
+		b.WriteString("  Least productive:\n")
+		for i := len(worst) - 1; i >= 0; i-- {
+			b.WriteString("  ")
+			line(worst[i])
+		}
+	}
+	if len(best) > 0 && best[0].Productive >= 2 {
+		b.WriteString(fmt.Sprintf("  Keep one as a stratagem: metacog stratagem promote %q\n", best[0].String()))
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// ratedBurst records a freestyle burst of the given primitives and its outcome.
+func ratedBurst(s *State, result string, actions ...string) {
+	for _, a := range actions {
+		s.AddHistory(HistoryEntry{Action: a, Params: map[string]string{}})
+	}
+	if err := RecordOutcome(s, result, ""); err != nil {
+		panic(err)
+	}
+}
+
+func TestMineFreestyleSequences(t *testing.T) {
+	s := NewState()
+	ratedBurst(s, "productive", "feel", "name", "ritual")
+	ratedBurst(s, "productive", "become", "feel", "name", "ritual")
+	ratedBurst(s, "unproductive", "feel", "name", "drugs")
+	ratedBurst(s, "unproductive", "drugs", "become", "drugs", "become")
+	ratedBurst(s, "productive", "drugs", "become")
+
+	stats := MineFreestyleSequences(s.History)
+	if q := FindSequenceStats(stats, []string{"feel", "name", "ritual"}); q.Productive != 2 || q.Total != 2 {
+		t.Errorf("expected feel → name → ritual 2/2, got %+v", q)
+	}
+	if q := FindSequenceStats(stats, []string{"feel", "name"}); q.Productive != 2 || q.Total != 3 {
+		t.Errorf("expected feel → name 2/3, got %+v", q)
+	}
+	// Counted once per burst even when it repeats inside one
+	if q := FindSequenceStats(stats, []string{"drugs", "become"}); q.Total != 2 {
+		t.Errorf("expected drugs → become in 2 bursts, got %+v", q)
+	}
+	// name → ritual has the same record as feel → name → ritual and adds nothing
+	if q := FindSequenceStats(stats, []string{"name", "ritual"}); q.Total != 0 {
+		t.Errorf("expected name → ritual to be subsumed, got %+v", q)
+	}
+	if stats[0].String() != "feel → name → ritual" {
+		t.Errorf("expected the longest fully productive sequence first, got %s", stats[0])
+	}
+}
+
+func TestFormatFreestyleSequences(t *testing.T) {
+	s := NewState()
+	ratedBurst(s, "productive", "feel", "name", "ritual")
+	ratedBurst(s, "productive", "feel", "name", "ritual")
+	ratedBurst(s, "unproductive", "drugs", "become")
+	ratedBurst(s, "unproductive", "drugs", "become")
+	ratedBurst(s, "productive", "meditate", "feel")
+
+	out := FormatFreestyleSequences(MineFreestyleSequences(s.History))
+	for _, want := range []string{
+		"feel → name → ritual: 100% productive (2/2)",
+		"Least productive:\n    drugs → become: 0% productive (0/2)",
+		`metacog stratagem promote "feel → name → ritual"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "meditate") {
+		t.Errorf("a sequence seen once should not be reported:\n%s", out)
+	}
+	if FormatFreestyleSequences(nil) != "" {
+		t.Error("expected nothing without recurring sequences")
+	}
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -23,41 +26,465 @@ func StartSession(s *State, name string) error {
 	return nil
 }
 
+// StartSubSession opens name inside the active session, for a focused sitting
+// within a longer engagement. Ending it returns to the enclosing session.
+func StartSubSession(s *State, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("session name cannot be empty")
+	}
+	if s.Session == "" {
+		return fmt.Errorf("no active session to nest %q in. Start one with 'metacog session start'", name)
+	}
+	if name == s.Session || containsString(s.SessionParents, name) {
+		return fmt.Errorf("session %q is already open", name)
+	}
+	parent := s.Session
+	s.SessionParents = append(s.SessionParents, parent)
+	s.Session = name
+	s.AddHistory(HistoryEntry{
+		Action: "session",
+		Params: map[string]string{"name": name, "event": "started", "parent": parent},
+	})
+	return nil
+}
+
 func EndSession(s *State) error {
+	return CloseSession(s, SessionClose{})
+}
+
+// CloseSession ends the active session, recording the closing answers on the
+// ended event so the retrospective can be regenerated from history.
+func CloseSession(s *State, c SessionClose) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+	return closeSession(s, c, "", "")
+}
+
+// closeSession records the ended event at timestamp (now when empty), with an
+// optional reason for ends the user didn't ask for.
+func closeSession(s *State, c SessionClose, timestamp, reason string) error {
 	if s.Session == "" {
 		return fmt.Errorf("no active session")
 	}
 	name := s.Session
+	params := map[string]string{"name": name, "event": "ended"}
+	c.addParams(params)
+	if reason != "" {
+		params["reason"] = reason
+	}
 	s.AddHistory(HistoryEntry{
-		Action: "session",
-		Params: map[string]string{"name": name, "event": "ended"},
+		Action:    "session",
+		Params:    params,
+		Timestamp: timestamp,
 	})
+	delete(s.SessionGoals, name)
 	s.Session = ""
+	if n := len(s.SessionParents); n > 0 {
+		s.Session = s.SessionParents[n-1]
+		s.SessionParents = s.SessionParents[:n-1]
+	}
 	return nil
 }
 
-func ListSessions(s *State) []string {
-	seen := map[string]bool{}
-	var names []string
-	for _, h := range s.History {
-		if h.Action == "session" && h.Params["event"] == "started" {
-			name := h.Params["name"]
-			if !seen[name] {
-				seen[name] = true
-				names = append(names, name)
+// SessionAbandonedAfter is how long without activity before status warns that
+// the active session looks abandoned, when no idle timeout is configured.
+const SessionAbandonedAfter = 12 * time.Hour
+
+// lastActivity is the timestamp of the most recent history entry.
+func lastActivity(s *State) (time.Time, bool) {
+	for i := len(s.History) - 1; i >= 0; i-- {
+		if t, err := time.Parse(time.RFC3339, s.History[i].Timestamp); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// EndIdleSessions ends every open session, innermost first, when nothing has
+// happened for longer than timeout. The ended events are dated at the last
+// activity, with reason=idle, so durations don't count the idle stretch.
+func EndIdleSessions(s *State, timeout time.Duration, now time.Time) []string {
+	if s.Session == "" || timeout <= 0 {
+		return nil
+	}
+	last, ok := lastActivity(s)
+	if !ok || now.Sub(last) < timeout {
+		return nil
+	}
+	at := last.UTC().Format(time.RFC3339)
+	var ended []string
+	for s.Session != "" {
+		ended = append(ended, s.Session)
+		closeSession(s, SessionClose{}, at, "idle")
+	}
+	return ended
+}
+
+// AbandonedSessionWarning describes the active session when it has been idle
+// for idleTimeout, or SessionAbandonedAfter when no timeout is configured, and
+// "" otherwise.
+func AbandonedSessionWarning(s *State, now time.Time, idleTimeout time.Duration) string {
+	if s.Session == "" {
+		return ""
+	}
+	threshold := idleTimeout
+	if threshold <= 0 {
+		threshold = SessionAbandonedAfter
+	}
+	last, ok := lastActivity(s)
+	if !ok || now.Sub(last) < threshold {
+		return ""
+	}
+	hint := "End it with 'metacog session end', or set session.idle_timeout in config.json to end idle sessions automatically."
+	if idleTimeout > 0 {
+		hint = fmt.Sprintf("It passed the %s idle_timeout and will be ended by the next command that records anything.", idleTimeout)
+	}
+	return fmt.Sprintf("Warning: session %q has had no activity for %s (since %s) and looks abandoned.\n  %s",
+		s.Session, now.Sub(last).Round(time.Hour), last.Format(time.RFC3339), hint)
+}
+
+// checkNewSessionName refuses a name an earlier session already has, since
+// starting it again would merge the two in SessionInfos. history must include
+// archived entries.
+func checkNewSessionName(history []HistoryEntry, name string) error {
+	name = strings.TrimSpace(name)
+	if info, ok := findSessionInfo(SessionInfos(history), name); ok && !info.Active {
+		return fmt.Errorf("session %q already exists. Run 'metacog session resume %s' to reopen it, or choose another name", name, name)
+	}
+	return nil
+}
+
+// ResumeSession reopens a previously ended session. history must include
+// archived entries so older sessions can be found. A sub-session can only be
+// resumed while its parent is active.
+func ResumeSession(s *State, history []HistoryEntry, name string) error {
+	name = strings.TrimSpace(name)
+	info, ok := findSessionInfo(SessionInfos(history), name)
+	if !ok {
+		return fmt.Errorf("no session %q in history. Run 'metacog session list' to see sessions", name)
+	}
+	if name == s.Session || containsString(s.SessionParents, name) {
+		return fmt.Errorf("session %q is already active", name)
+	}
+	if info.Parent != "" {
+		if s.Session != info.Parent {
+			return fmt.Errorf("%q is a sub-session of %q. Resume %q first", name, info.Parent, info.Parent)
+		}
+		s.SessionParents = append(s.SessionParents, s.Session)
+	} else if s.Session != "" {
+		return fmt.Errorf("session %q is already active. End it first with 'metacog session end'", s.Session)
+	}
+	s.Session = name
+	setSessionGoal(s, name, info.Goal)
+	s.AddHistory(HistoryEntry{
+		Action: "session",
+		Params: map[string]string{"name": name, "event": "resumed"},
+	})
+	return nil
+}
+
+// RenameSession records a rename event rather than rewriting history; entries
+// tagged with the old name resolve to the new one through sessionRenames.
+func RenameSession(s *State, history []HistoryEntry, from, to string) error {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return fmt.Errorf("session name cannot be empty")
+	}
+	infos := SessionInfos(history)
+	if _, ok := findSessionInfo(infos, from); !ok {
+		return fmt.Errorf("no session %q in history. Run 'metacog session list' to see sessions", from)
+	}
+	if _, taken := findSessionInfo(infos, to); taken {
+		return fmt.Errorf("session %q already exists", to)
+	}
+	if s.Session == from {
+		s.Session = to
+	}
+	for i, p := range s.SessionParents {
+		if p == from {
+			s.SessionParents[i] = to
+		}
+	}
+	if goal, ok := s.SessionGoals[from]; ok {
+		delete(s.SessionGoals, from)
+		s.SessionGoals[to] = goal
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "session",
+		Params: map[string]string{"name": from, "event": "renamed", "to": to},
+	})
+	return nil
+}
+
+type sessionRename struct {
+	index     int
+	timestamp string
+	from, to  string
+}
+
+func sessionRenames(history []HistoryEntry) []sessionRename {
+	var renames []sessionRename
+	for i, h := range history {
+		if h.Action == "session" && h.Params["event"] == "renamed" {
+			renames = append(renames, sessionRename{i, h.Timestamp, h.Params["name"], h.Params["to"]})
+		}
+	}
+	return renames
+}
+
+// resolveSession maps a session name as written at history index i to its
+// current name, applying only the renames that came after it.
+func resolveSession(renames []sessionRename, name string, i int) string {
+	for _, r := range renames {
+		if r.index > i && r.from == name {
+			name = r.to
+		}
+	}
+	return name
+}
+
+// resolveSessionAt does the same for records kept outside history, such as
+// journal entries, positioned by timestamp.
+func resolveSessionAt(renames []sessionRename, name, timestamp string) string {
+	for _, r := range renames {
+		if r.timestamp >= timestamp && r.from == name {
+			name = r.to
+		}
+	}
+	return name
+}
+
+// SessionInfo describes a session as reconstructed from history, under its current name.
+type SessionInfo struct {
+	Name   string
+	Parent string
+	Active bool
+	Goal   string
+	// Close holds the answers recorded when the session last ended
+	Close SessionClose
+	// Sittings are start-or-resume/end timestamp pairs; an open sitting has no end
+	Sittings [][2]string
+}
+
+// SessionInfos lists sessions in the order they were first started.
+func SessionInfos(history []HistoryEntry) []SessionInfo {
+	renames := sessionRenames(history)
+	var infos []SessionInfo
+	index := map[string]int{}
+	for i, h := range history {
+		if h.Action != "session" {
+			continue
+		}
+		name := resolveSession(renames, h.Params["name"], i)
+		switch h.Params["event"] {
+		case "started", "resumed":
+			n, ok := index[name]
+			if !ok {
+				n = len(infos)
+				index[name] = n
+				infos = append(infos, SessionInfo{Name: name})
+			}
+			if parent := h.Params["parent"]; parent != "" {
+				infos[n].Parent = resolveSession(renames, parent, i)
+			}
+			if goal := h.Params["goal"]; goal != "" {
+				infos[n].Goal = goal
+			}
+			infos[n].Active = true
+			infos[n].Sittings = append(infos[n].Sittings, [2]string{h.Timestamp, ""})
+		case "ended":
+			if n, ok := index[name]; ok && infos[n].Active {
+				infos[n].Active = false
+				infos[n].Sittings[len(infos[n].Sittings)-1][1] = h.Timestamp
+				infos[n].Close = sessionCloseFromParams(h.Params)
 			}
 		}
 	}
+	return infos
+}
+
+func findSessionInfo(infos []SessionInfo, name string) (SessionInfo, bool) {
+	for _, info := range infos {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return SessionInfo{}, false
+}
+
+func ListSessions(s *State) []string {
+	infos := SessionInfos(s.History)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
 	return names
 }
 
+func FormatSessionList(infos []SessionInfo) string {
+	if len(infos) == 0 {
+		return "No sessions recorded."
+	}
+	children := map[string][]SessionInfo{}
+	var roots []SessionInfo
+	for _, info := range infos {
+		if info.Parent == "" {
+			roots = append(roots, info)
+		} else {
+			children[info.Parent] = append(children[info.Parent], info)
+		}
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d sessions:\n", len(infos)))
+	var write func(info SessionInfo, depth int)
+	write = func(info SessionInfo, depth int) {
+		b.WriteString(strings.Repeat("  ", depth+1) + info.Name)
+		if info.Active {
+			b.WriteString(" (active)")
+		}
+		b.WriteString("\n")
+		for _, child := range children[info.Name] {
+			write(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		write(root, 0)
+	}
+	return b.String()
+}
+
+// SessionSummary aggregates what happened in one session.
+type SessionSummary struct {
+	Info                SessionInfo
+	Duration            time.Duration
+	Primitives          map[string]int
+	StratagemsCompleted []string
+	Outcomes            map[string]int
+	Journal             []JournalEntry
+	SubSessions         []string
+	// Entries are the history entries tagged with the session
+	Entries []HistoryEntry
+}
+
+// SummarizeSession collects the history and journal entries tagged with name,
+// including those written under an earlier name. now closes an open sitting.
+func SummarizeSession(history []HistoryEntry, journal []JournalEntry, name string, now time.Time) (*SessionSummary, error) {
+	infos := SessionInfos(history)
+	info, ok := findSessionInfo(infos, name)
+	if !ok {
+		return nil, fmt.Errorf("no session %q in history. Run 'metacog session list' to see sessions", name)
+	}
+	sum := &SessionSummary{Info: info, Primitives: map[string]int{}, Outcomes: map[string]int{}}
+
+	for _, sitting := range info.Sittings {
+		start, err := time.Parse(time.RFC3339, sitting[0])
+		if err != nil {
+			continue
+		}
+		end := now
+		if sitting[1] != "" {
+			if end, err = time.Parse(time.RFC3339, sitting[1]); err != nil {
+				continue
+			}
+		}
+		sum.Duration += end.Sub(start)
+	}
+	for _, other := range infos {
+		if other.Parent == name {
+			sum.SubSessions = append(sum.SubSessions, other.Name)
+		}
+	}
+
+	renames := sessionRenames(history)
+	for i, h := range history {
+		if h.Session == "" || resolveSession(renames, h.Session, i) != name {
+			continue
+		}
+		sum.Entries = append(sum.Entries, h)
+		switch {
+		case primitiveActions[h.Action]:
+			sum.Primitives[h.Action]++
+		case h.Action == "stratagem" && h.Params["event"] == "completed":
+			sum.StratagemsCompleted = append(sum.StratagemsCompleted, h.Params["name"])
+		case h.Action == "outcome":
+			sum.Outcomes[h.Params["result"]]++
+		}
+	}
+	for _, e := range journal {
+		if e.Session != "" && resolveSessionAt(renames, e.Session, e.Timestamp) == name {
+			sum.Journal = append(sum.Journal, e)
+		}
+	}
+	return sum, nil
+}
+
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s %d", k, counts[k])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func FormatSessionSummary(sum *SessionSummary) string {
+	var b strings.Builder
+	status := "ended"
+	if sum.Info.Active {
+		status = "active"
+	}
+	b.WriteString(fmt.Sprintf("Session %q (%s)\n", sum.Info.Name, status))
+	if sum.Info.Parent != "" {
+		b.WriteString(fmt.Sprintf("Within: %s\n", sum.Info.Parent))
+	}
+	if sum.Info.Goal != "" {
+		b.WriteString(fmt.Sprintf("Goal: %s\n", sum.Info.Goal))
+	}
+	if !sum.Info.Active {
+		b.WriteString(sum.Info.Close.String())
+	}
+	if len(sum.Info.Sittings) > 0 {
+		b.WriteString(fmt.Sprintf("Started: %s\n", sum.Info.Sittings[0][0]))
+	}
+	b.WriteString(fmt.Sprintf("Duration: %s over %d sittings\n", sum.Duration.Round(time.Minute), len(sum.Info.Sittings)))
+
+	if len(sum.Primitives) == 0 {
+		b.WriteString("Primitives: none\n")
+	} else {
+		b.WriteString(fmt.Sprintf("Primitives: %s\n", formatCounts(sum.Primitives)))
+	}
+	if len(sum.StratagemsCompleted) > 0 {
+		b.WriteString(fmt.Sprintf("Stratagems completed: %s\n", strings.Join(sum.StratagemsCompleted, ", ")))
+	}
+	if len(sum.Outcomes) > 0 {
+		b.WriteString(fmt.Sprintf("Outcomes: %s\n", formatCounts(sum.Outcomes)))
+	}
+	if len(sum.SubSessions) > 0 {
+		b.WriteString(fmt.Sprintf("Sub-sessions: %s\n", strings.Join(sum.SubSessions, ", ")))
+	}
+	if len(sum.Journal) > 0 {
+		b.WriteString(fmt.Sprintf("Journal (%d):\n", len(sum.Journal)))
+		for _, e := range sum.Journal {
+			b.WriteString(fmt.Sprintf("  [%s] %s\n", e.Timestamp, indentInsight(e.Insight, "    ")))
+		}
+	}
+	return b.String()
+}
+
 func FormatHistoryFiltered(s *State, session string) string {
 	if len(s.History) == 0 {
 		return "No history."
 	}
+	renames := sessionRenames(s.History)
 	var filtered []HistoryEntry
-	for _, h := range s.History {
-		if h.Session == session {
+	for i, h := range s.History {
+		if h.Session != "" && resolveSession(renames, h.Session, i) == session {
 			filtered = append(filtered, h)
 		}
 	}
@@ -73,6 +500,9 @@ var sessionCmd = &cobra.Command{
 	Short: "Manage named sessions",
 }
 
+var sessionStartSub bool
+var sessionStartGoal string
+
 var sessionStartCmd = &cobra.Command{
 	Use:   "start [name]",
 	Short: "Start a named session",
@@ -81,10 +511,26 @@ var sessionStartCmd = &cobra.Command{
 		sm := DefaultStateManager()
 		var output string
 		err := sm.SaveWithLock(func(s *State) error {
-			err := StartSession(s, args[0])
+			merged, err := mergeArchivedHistory(sm, s)
 			if err != nil {
 				return err
 			}
+			if err := checkNewSessionName(merged.History, args[0]); err != nil {
+				return err
+			}
+			if sessionStartSub {
+				parent := s.Session
+				if err := StartSubSession(s, args[0]); err != nil {
+					return err
+				}
+				recordSessionGoal(s, sessionStartGoal)
+				output = fmt.Sprintf("Session %q started within %q.", args[0], parent)
+				return nil
+			}
+			if err := StartSession(s, args[0]); err != nil {
+				return err
+			}
+			recordSessionGoal(s, sessionStartGoal)
 			output = fmt.Sprintf("Session %q started.", args[0])
 			return nil
 		})
@@ -96,55 +542,168 @@ var sessionStartCmd = &cobra.Command{
 	},
 }
 
+var sessionEndClose SessionClose
+
 var sessionEndCmd = &cobra.Command{
 	Use:   "end",
-	Short: "End the active session",
+	Short: "End the active session and write its retrospective",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sm := DefaultStateManager()
-		var output string
+		if sessionEndClose.empty() && stdinIsTerminal() {
+			s, err := sm.Load()
+			if err != nil {
+				return err
+			}
+			if s.Session != "" {
+				sessionEndClose = promptSessionClose(cmd.InOrStdin(), cmd.OutOrStdout(), s.SessionGoals[s.Session])
+			}
+		}
+
+		var name, output string
 		err := sm.SaveWithLock(func(s *State) error {
-			name := s.Session
-			err := EndSession(s)
+			name = s.Session
+			err := CloseSession(s, sessionEndClose)
 			if err != nil {
 				return err
 			}
 			output = fmt.Sprintf("Session %q ended.", name)
+			if s.Session != "" {
+				output += fmt.Sprintf(" Back in %q.", s.Session)
+			}
 			return nil
 		})
 		if err != nil {
 			return err
 		}
+
+		path, err := writeRetrospective(sm, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not write retrospective: %v\n", err)
+		} else {
+			output += fmt.Sprintf("\nRetrospective: %s", path)
+		}
 		fmt.Println(FormatOutput(jsonOutput, output, nil))
 		return nil
 	},
 }
 
-var sessionListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all sessions from history",
+var sessionResumeCmd = &cobra.Command{
+	Use:   "resume NAME",
+	Short: "Reopen an ended session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		err := sm.SaveWithLock(func(s *State) error {
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			return ResumeSession(s, merged.History, args[0])
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Session %q resumed.", args[0]), nil))
+		return nil
+	},
+}
+
+var sessionRenameCmd = &cobra.Command{
+	Use:   "rename OLD NEW",
+	Short: "Rename a session; earlier entries follow the new name",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		err := sm.SaveWithLock(func(s *State) error {
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			return RenameSession(s, merged.History, args[0], args[1])
+		})
+		if err != nil {
+			return err
+		}
+		if err := os.Rename(retrospectivePath(sm, args[0]), retrospectivePath(sm, args[1])); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: could not rename retrospective: %v\n", err)
+		}
+		fmt.Println(FormatOutput(jsonOutput, fmt.Sprintf("Session %q renamed to %q.", args[0], args[1]), nil))
+		return nil
+	},
+}
+
+var sessionShowSummary bool
+
+var sessionShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a session's retrospective, or summarize it: duration, primitives, stratagems, outcomes and journal entries",
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sm := DefaultStateManager()
 		s, err := sm.Load()
 		if err != nil {
 			return err
 		}
-		names := ListSessions(s)
-		if len(names) == 0 {
-			fmt.Println(FormatOutput(jsonOutput, "No sessions recorded.", nil))
-			return nil
+		name := s.Session
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if name == "" {
+			return fmt.Errorf("no active session. Name one: 'metacog session show NAME'")
+		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		journal, err := sm.LoadJournal()
+		if err != nil {
+			return err
 		}
-		output := fmt.Sprintf("%d sessions:\n", len(names))
-		for _, name := range names {
-			output += fmt.Sprintf("  %s\n", name)
+		sum, err := SummarizeSession(merged.History, journal, name, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		output := FormatSessionSummary(sum)
+		if !sum.Info.Active && !sessionShowSummary {
+			if retro, err := os.ReadFile(retrospectivePath(sm, name)); err == nil {
+				output = strings.TrimRight(string(retro), "\n")
+			}
 		}
 		fmt.Println(FormatOutput(jsonOutput, output, nil))
 		return nil
 	},
 }
 
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all sessions from history, including archived history",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		s, err := sm.Load()
+		if err != nil {
+			return err
+		}
+		merged, err := mergeArchivedHistory(sm, s)
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, FormatSessionList(SessionInfos(merged.History)), nil))
+		return nil
+	},
+}
+
 func init() {
+	sessionStartCmd.Flags().BoolVar(&sessionStartSub, "sub", false, "Start a sub-session within the active session")
+	sessionStartCmd.Flags().StringVar(&sessionStartGoal, "goal", "", "What this session is for; asked about again at 'session end'")
+	sessionEndCmd.Flags().StringVar(&sessionEndClose.GoalMet, "goal-met", "", "Whether the goal was met: yes, partly or no")
+	sessionEndCmd.Flags().StringVar(&sessionEndClose.Shifted, "shifted", "", "What shifted during the session")
+	sessionEndCmd.Flags().StringVar(&sessionEndClose.Keep, "keep", "", "What to keep from the session")
+	sessionShowCmd.Flags().BoolVar(&sessionShowSummary, "summary", false, "Show the summary even when a retrospective was written")
 	sessionCmd.AddCommand(sessionStartCmd)
 	sessionCmd.AddCommand(sessionEndCmd)
+	sessionCmd.AddCommand(sessionResumeCmd)
+	sessionCmd.AddCommand(sessionRenameCmd)
+	sessionCmd.AddCommand(sessionShowCmd)
 	sessionCmd.AddCommand(sessionListCmd)
 	rootCmd.AddCommand(sessionCmd)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestSessionStart(t *testing.T) {
@@ -98,3 +99,235 @@ func TestSessionList(t *testing.T) {
 		t.Errorf("expected 2 sessions, got %d", len(names))
 	}
 }
+
+func TestSubSession(t *testing.T) {
+	s := NewState()
+	if err := StartSubSession(s, "sitting"); err == nil {
+		t.Error("expected error starting a sub-session with no active session")
+	}
+	StartSession(s, "engagement")
+	if err := StartSubSession(s, "engagement"); err == nil {
+		t.Error("expected error nesting a session in itself")
+	}
+	if err := StartSubSession(s, "sitting-1"); err != nil {
+		t.Fatalf("sub-session: %v", err)
+	}
+	s.AddHistory(HistoryEntry{Action: "become", Params: map[string]string{"name": "Ada"}})
+	if s.History[len(s.History)-1].Session != "sitting-1" {
+		t.Error("history should be tagged with the innermost session")
+	}
+
+	EndSession(s)
+	if s.Session != "engagement" || len(s.SessionParents) != 0 {
+		t.Errorf("ending a sub-session should return to its parent, got %q %v", s.Session, s.SessionParents)
+	}
+
+	infos := SessionInfos(s.History)
+	if len(infos) != 2 || infos[1].Parent != "engagement" {
+		t.Fatalf("expected sitting-1 within engagement, got %+v", infos)
+	}
+	list := FormatSessionList(infos)
+	if !strings.Contains(list, "  engagement (active)\n    sitting-1\n") {
+		t.Errorf("expected nested listing, got %q", list)
+	}
+}
+
+func TestCheckNewSessionName(t *testing.T) {
+	s := NewState()
+	StartSession(s, "alpha")
+	StartSubSession(s, "inner")
+	EndSession(s)
+	if err := checkNewSessionName(s.History, "fresh"); err != nil {
+		t.Errorf("an unused name should be accepted: %v", err)
+	}
+	if err := checkNewSessionName(s.History, " inner "); err == nil || !strings.Contains(err.Error(), "session resume inner") {
+		t.Errorf("expected an ended session's name to be refused, got %v", err)
+	}
+	RenameSession(s, s.History, "alpha", "beta")
+	EndSession(s)
+	if err := checkNewSessionName(s.History, "alpha"); err != nil {
+		t.Errorf("a renamed session's old name is free again: %v", err)
+	}
+	if err := checkNewSessionName(s.History, "beta"); err == nil {
+		t.Error("expected the renamed session's new name to be refused")
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	s := NewState()
+	if err := ResumeSession(s, s.History, "never"); err == nil {
+		t.Error("expected error resuming an unknown session")
+	}
+	StartSession(s, "alpha")
+	StartSubSession(s, "inner")
+	EndSession(s)
+	EndSession(s)
+
+	if err := ResumeSession(s, s.History, "inner"); err == nil || !strings.Contains(err.Error(), "Resume \"alpha\" first") {
+		t.Errorf("expected sub-session to require its parent, got %v", err)
+	}
+	if err := ResumeSession(s, s.History, "alpha"); err != nil {
+		t.Fatalf("resume alpha: %v", err)
+	}
+	if err := ResumeSession(s, s.History, "inner"); err != nil {
+		t.Fatalf("resume inner: %v", err)
+	}
+	if s.Session != "inner" || len(s.SessionParents) != 1 || s.SessionParents[0] != "alpha" {
+		t.Errorf("expected inner within alpha, got %q %v", s.Session, s.SessionParents)
+	}
+
+	info, _ := findSessionInfo(SessionInfos(s.History), "alpha")
+	if len(info.Sittings) != 2 || !info.Active {
+		t.Errorf("expected 2 sittings with alpha active, got %+v", info)
+	}
+}
+
+func TestRenameSession(t *testing.T) {
+	s := NewState()
+	StartSession(s, "draft")
+	s.AddHistory(HistoryEntry{Action: "become", Params: map[string]string{"name": "Ada"}})
+	EndSession(s)
+	StartSession(s, "other")
+	EndSession(s)
+
+	if err := RenameSession(s, s.History, "draft", "other"); err == nil {
+		t.Error("expected error renaming onto an existing session")
+	}
+	if err := RenameSession(s, s.History, "draft", "final"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	// The old name is free again and must not absorb the renamed session
+	StartSession(s, "draft")
+	s.AddHistory(HistoryEntry{Action: "feel", Params: map[string]string{}})
+	EndSession(s)
+
+	names := ListSessions(s)
+	if strings.Join(names, ",") != "final,other,draft" {
+		t.Errorf("expected final,other,draft, got %v", names)
+	}
+	if out := FormatHistoryFiltered(s, "final"); !strings.Contains(out, "Ada") || strings.Contains(out, "feel") {
+		t.Errorf("history for final should hold only the renamed entries:\n%s", out)
+	}
+}
+
+func TestRenameActiveSession(t *testing.T) {
+	s := NewState()
+	StartSession(s, "outer")
+	StartSubSession(s, "inner")
+	if err := RenameSession(s, s.History, "outer", "engagement"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if s.SessionParents[0] != "engagement" {
+		t.Errorf("active parent should be renamed, got %v", s.SessionParents)
+	}
+	info, _ := findSessionInfo(SessionInfos(s.History), "inner")
+	if info.Parent != "engagement" {
+		t.Errorf("sub-session parent should follow the rename, got %q", info.Parent)
+	}
+}
+
+func TestSummarizeSession(t *testing.T) {
+	history := []HistoryEntry{
+		{Action: "session", Session: "deep", Timestamp: "2025-01-01T10:00:00Z", Params: map[string]string{"name": "deep", "event": "started"}},
+		{Action: "become", Session: "deep", Timestamp: "2025-01-01T10:05:00Z", Params: map[string]string{"name": "Ada"}},
+		{Action: "stratagem", Session: "deep", Timestamp: "2025-01-01T10:20:00Z", Params: map[string]string{"name": "pivot", "event": "completed"}},
+		{Action: "outcome", Session: "deep", Timestamp: "2025-01-01T10:25:00Z", Params: map[string]string{"result": "productive"}},
+		{Action: "session", Session: "deep", Timestamp: "2025-01-01T10:30:00Z", Params: map[string]string{"name": "deep", "event": "ended"}},
+		{Action: "become", Timestamp: "2025-01-01T11:00:00Z", Params: map[string]string{"name": "Eno"}},
+		{Action: "session", Session: "deep", Timestamp: "2025-01-02T09:00:00Z", Params: map[string]string{"name": "deep", "event": "resumed"}},
+		{Action: "feel", Session: "deep", Timestamp: "2025-01-02T09:10:00Z", Params: map[string]string{}},
+	}
+	journal := []JournalEntry{
+		{Timestamp: "2025-01-01T10:10:00Z", Insight: "in deep", Session: "deep"},
+		{Timestamp: "2025-01-01T11:10:00Z", Insight: "elsewhere"},
+	}
+	now, _ := time.Parse(time.RFC3339, "2025-01-02T09:15:00Z")
+
+	sum, err := SummarizeSession(history, journal, "deep", now)
+	if err != nil {
+		t.Fatalf("summarize: %v", err)
+	}
+	if sum.Duration != 45*time.Minute {
+		t.Errorf("expected 45m over two sittings, got %s", sum.Duration)
+	}
+	if sum.Primitives["become"] != 1 || sum.Primitives["feel"] != 1 {
+		t.Errorf("expected become 1 and feel 1, got %v", sum.Primitives)
+	}
+	if len(sum.StratagemsCompleted) != 1 || sum.Outcomes["productive"] != 1 || len(sum.Journal) != 1 {
+		t.Errorf("unexpected summary %+v", sum)
+	}
+
+	out := FormatSessionSummary(sum)
+	for _, want := range []string{`Session "deep" (active)`, "Duration: 45m0s over 2 sittings", "Primitives: become 1, feel 1", "Stratagems completed: pivot", "Outcomes: productive 1", "in deep"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary should contain %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := SummarizeSession(history, journal, "nope", now); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
+
+func TestEndIdleSessions(t *testing.T) {
+	s := NewState()
+	StartSession(s, "outer")
+	StartSubSession(s, "inner")
+	s.AddHistory(HistoryEntry{Action: "become", Timestamp: "2025-01-01T10:00:00Z", Params: map[string]string{"name": "Ada"}})
+	last, _ := time.Parse(time.RFC3339, "2025-01-01T10:00:00Z")
+
+	if ended := EndIdleSessions(s, 8*time.Hour, last.Add(7*time.Hour)); ended != nil {
+		t.Errorf("should not end before the timeout, ended %v", ended)
+	}
+	if ended := EndIdleSessions(s, 0, last.Add(100*time.Hour)); ended != nil {
+		t.Errorf("a zero timeout disables auto-ending, ended %v", ended)
+	}
+
+	ended := EndIdleSessions(s, 8*time.Hour, last.Add(9*time.Hour))
+	if strings.Join(ended, ",") != "inner,outer" {
+		t.Errorf("expected inner then outer to end, got %v", ended)
+	}
+	if s.Session != "" || len(s.SessionParents) != 0 {
+		t.Errorf("no session should remain open, got %q %v", s.Session, s.SessionParents)
+	}
+	for _, h := range s.History[len(s.History)-2:] {
+		if h.Params["event"] != "ended" || h.Params["reason"] != "idle" || h.Timestamp != "2025-01-01T10:00:00Z" {
+			t.Errorf("expected idle ended event at last activity, got %+v", h)
+		}
+		if h.Session != "" && h.Session != h.Params["name"] {
+			t.Errorf("ended event tagged with the wrong session: %+v", h)
+		}
+	}
+}
+
+func TestAbandonedSessionWarning(t *testing.T) {
+	s := NewState()
+	now := time.Now().UTC()
+	if AbandonedSessionWarning(s, now, 0) != "" {
+		t.Error("no warning without an active session")
+	}
+	StartSession(s, "stale")
+	if AbandonedSessionWarning(s, now, 0) != "" {
+		t.Error("no warning for a fresh session")
+	}
+	warning := AbandonedSessionWarning(s, now.Add(SessionAbandonedAfter+time.Hour), 0)
+	if !strings.Contains(warning, `session "stale"`) || !strings.Contains(warning, "idle_timeout") {
+		t.Errorf("unexpected warning %q", warning)
+	}
+}
+
+func TestAbandonedSessionWarningUsesIdleTimeout(t *testing.T) {
+	s := NewState()
+	StartSession(s, "stale")
+	now := time.Now().UTC()
+	if AbandonedSessionWarning(s, now.Add(2*time.Hour), time.Hour) == "" {
+		t.Error("expected a warning once the configured idle timeout has passed")
+	}
+	if AbandonedSessionWarning(s, now.Add(2*time.Hour), 4*time.Hour) != "" {
+		t.Error("no warning before the configured idle timeout")
+	}
+	warning := AbandonedSessionWarning(s, now.Add(5*time.Hour), 4*time.Hour)
+	if !strings.Contains(warning, "next command that records") {
+		t.Errorf("warning should say the session will be ended, got %q", warning)
+	}
+}
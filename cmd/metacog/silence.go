@@ -56,9 +56,11 @@ func formatSilence(about, reason, duration string) string {
 }
 
 func applySilence(s *State, about, reason, duration string) {
+	id := OpenConstraint(s, "silence", fmt.Sprintf("%s (%s)", about, duration))
 	s.AddHistory(HistoryEntry{
 		Action: "silence",
 		Params: map[string]string{
+			"id":       id,
 			"about":    about,
 			"reason":   reason,
 			"duration": duration,
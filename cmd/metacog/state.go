@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -31,6 +32,17 @@ type ActiveStratagem struct {
 	Step           int      `json:"step"`
 	StepsCompleted []string `json:"steps_completed"`
 	StartedAt      string   `json:"started_at"`
+	// RunID tells runs of the same stratagem apart; recorded as "run" on its history events
+	RunID string `json:"run_id,omitempty"`
+}
+
+// historyParams identifies the run in a stratagem history entry.
+func (a *ActiveStratagem) historyParams() map[string]string {
+	params := map[string]string{"name": a.Name}
+	if a.RunID != "" {
+		params["run"] = a.RunID
+	}
+	return params
 }
 
 type HistoryEntry struct {
@@ -41,16 +53,37 @@ type HistoryEntry struct {
 	// For abandoned stratagems
 	Status string `json:"status,omitempty"`
 	StepAt int    `json:"step_at,omitempty"`
+	// Amendments record edits made to Params after the entry was written, oldest first
+	Amendments []Amendment `json:"amendments,omitempty"`
+}
+
+// Amendment is one revision of a history entry's param. An empty From or To
+// means the param was unset.
+type Amendment struct {
+	Timestamp string `json:"timestamp"`
+	Field     string `json:"field"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Reason    string `json:"reason,omitempty"`
 }
 
 type State struct {
-	Version   int              `json:"version"`
-	SessionID string           `json:"session_id"`
-	Session   string           `json:"session,omitempty"`
-	Identity  *Identity        `json:"identity,omitempty"`
-	Substrate *Substrate       `json:"substrate,omitempty"`
-	Stratagem *ActiveStratagem `json:"stratagem,omitempty"`
-	History   []HistoryEntry   `json:"history"`
+	Version   int    `json:"version"`
+	SessionID string `json:"session_id"`
+	Session   string `json:"session,omitempty"`
+	// SessionParents holds the sessions enclosing an active sub-session, outermost first
+	SessionParents []string `json:"session_parents,omitempty"`
+	// SessionGoals maps open sessions to the goal they were started with
+	SessionGoals map[string]string `json:"session_goals,omitempty"`
+	// Identity and Substrate mirror the top of their stacks so older readers keep working
+	Identity       *Identity        `json:"identity,omitempty"`
+	Substrate      *Substrate       `json:"substrate,omitempty"`
+	IdentityStack  []Identity       `json:"identity_stack,omitempty"`
+	SubstrateStack []Substrate      `json:"substrate_stack,omitempty"`
+	Stratagem      *ActiveStratagem `json:"stratagem,omitempty"`
+	Constraints    []Constraint     `json:"constraints,omitempty"`
+	Threads        []Thread         `json:"threads,omitempty"`
+	History        []HistoryEntry   `json:"history"`
 }
 
 func NewState() *State {
@@ -77,6 +110,9 @@ type StateManager struct {
 	lockPath    string
 	archivePath string
 	journalPath string
+	configPath  string
+	// config caches LoadConfig
+	config *Config
 }
 
 func NewStateManager(dir string) *StateManager {
@@ -86,15 +122,22 @@ func NewStateManager(dir string) *StateManager {
 		lockPath:    filepath.Join(dir, ".state.lock"),
 		archivePath: filepath.Join(dir, "history-archive.jsonl"),
 		journalPath: filepath.Join(dir, "journal.jsonl"),
+		configPath:  filepath.Join(dir, "config.json"),
 	}
 }
 
-func DefaultStateManager() *StateManager {
+// metacogHome is $METACOG_HOME, or ~/.metacog when unset.
+func metacogHome() string {
 	dir := os.Getenv("METACOG_HOME")
 	if dir == "" {
 		home, _ := os.UserHomeDir()
 		dir = filepath.Join(home, ".metacog")
 	}
+	return dir
+}
+
+func DefaultStateManager() *StateManager {
+	dir := metacogHome()
 	os.MkdirAll(dir, 0755)
 	return NewStateManager(dir)
 }
@@ -154,6 +197,33 @@ func (sm *StateManager) loadUnlocked() (*State, error) {
 	return &s, nil
 }
 
+// expireIdleSession applies the configured session idle timeout, saving the
+// state if it ended anything, and returns the sessions it ended. Only write
+// paths call it, so reading state never changes it. Callers hold the lock.
+func (sm *StateManager) expireIdleSession(s *State) []string {
+	if s.Session == "" {
+		return nil
+	}
+	cfg, err := sm.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	timeout, _ := cfg.Session.IdleTimeoutDuration()
+	ended := EndIdleSessions(s, timeout, time.Now().UTC())
+	if len(ended) == 0 {
+		return nil
+	}
+	if err := sm.saveUnlocked(s); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not save state: %v\n", err)
+		return nil
+	}
+	for _, name := range ended {
+		fmt.Fprintf(os.Stderr, "Session %q ended: no activity for %s.\n", name, timeout)
+	}
+	return ended
+}
+
 func (sm *StateManager) Save(s *State) error {
 	lockFile, err := sm.lock()
 	if err != nil {
@@ -209,22 +279,38 @@ func (sm *StateManager) saveUnlocked(s *State) error {
 }
 
 func (sm *StateManager) SaveWithLock(fn func(s *State) error) error {
+	ended, err := sm.saveWithLock(fn)
+	// Retrospectives read state and the journal, which take the lock again.
+	for _, name := range ended {
+		if path, rerr := writeRetrospective(sm, name); rerr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not write retrospective for %q: %v\n", name, rerr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Retrospective: %s\n", path)
+		}
+	}
+	return err
+}
+
+// saveWithLock runs fn under the lock, after ending idle sessions, and
+// returns the sessions that were ended.
+func (sm *StateManager) saveWithLock(fn func(s *State) error) ([]string, error) {
 	lockFile, err := sm.lock()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer sm.unlock(lockFile)
 
 	s, err := sm.loadUnlocked()
 	if err != nil {
-		return fmt.Errorf("cannot load state: %w\n  Run 'metacog repair' to fix corrupted state, or 'metacog reset' to start fresh", err)
+		return nil, fmt.Errorf("cannot load state: %w\n  Run 'metacog repair' to fix corrupted state, or 'metacog reset' to start fresh", err)
 	}
+	ended := sm.expireIdleSession(s)
 
 	if err := fn(s); err != nil {
-		return err
+		return ended, err
 	}
 
-	return sm.saveUnlocked(s)
+	return ended, sm.saveUnlocked(s)
 }
 
 func (sm *StateManager) AppendJournal(entry JournalEntry) error {
@@ -234,6 +320,31 @@ func (sm *StateManager) AppendJournal(entry JournalEntry) error {
 	}
 	defer sm.unlock(lockFile)
 
+	return sm.appendJournalUnlocked(entry)
+}
+
+// UpdateJournal appends the record fn builds from the current journal, with
+// amendments and deletions folded in. Both happen under one lock, so fn never
+// checks a record against a journal another command has since changed.
+func (sm *StateManager) UpdateJournal(fn func(entries []JournalEntry) (JournalEntry, error)) error {
+	lockFile, err := sm.lock()
+	if err != nil {
+		return err
+	}
+	defer sm.unlock(lockFile)
+
+	entries, _, err := sm.loadJournalUnlocked()
+	if err != nil {
+		return err
+	}
+	record, err := fn(entries)
+	if err != nil {
+		return err
+	}
+	return sm.appendJournalUnlocked(record)
+}
+
+func (sm *StateManager) appendJournalUnlocked(entry JournalEntry) error {
 	os.MkdirAll(sm.dir, 0755)
 	f, err := os.OpenFile(sm.journalPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -249,33 +360,66 @@ func (sm *StateManager) AppendJournal(entry JournalEntry) error {
 	return err
 }
 
+// LoadJournal returns journal entries with amendments and deletions folded
+// in. Malformed lines are skipped; use LoadJournalStrict to surface them.
 func (sm *StateManager) LoadJournal() ([]JournalEntry, error) {
-	lockFile, err := sm.lock()
+	entries, _, err := sm.loadJournal()
+	return entries, err
+}
+
+// LoadJournalStrict is LoadJournal, but fails naming the line numbers of any
+// malformed lines instead of skipping them.
+func (sm *StateManager) LoadJournalStrict() ([]JournalEntry, error) {
+	entries, malformed, err := sm.loadJournal()
 	if err != nil {
 		return nil, err
 	}
+	if len(malformed) > 0 {
+		lines := make([]string, len(malformed))
+		for i, n := range malformed {
+			lines[i] = strconv.Itoa(n)
+		}
+		return nil, fmt.Errorf("journal %s has %d malformed lines: %s", sm.journalPath, len(malformed), strings.Join(lines, ", "))
+	}
+	return entries, nil
+}
+
+func (sm *StateManager) loadJournal() ([]JournalEntry, []int, error) {
+	lockFile, err := sm.lock()
+	if err != nil {
+		return nil, nil, err
+	}
 	defer sm.unlock(lockFile)
 
+	return sm.loadJournalUnlocked()
+}
+
+func (sm *StateManager) loadJournalUnlocked() ([]JournalEntry, []int, error) {
 	data, err := os.ReadFile(sm.journalPath)
 	if os.IsNotExist(err) {
-		return nil, nil
+		return nil, nil, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("cannot read journal: %w", err)
+		return nil, nil, fmt.Errorf("cannot read journal: %w", err)
 	}
 
-	var entries []JournalEntry
-	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
-		if line == "" {
+	var records []JournalEntry
+	var malformed []int
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
 		var entry JournalEntry
 		if err := json.Unmarshal([]byte(line), &entry); err != nil {
-			continue // skip malformed lines
+			malformed = append(malformed, i+1)
+			continue
 		}
-		entries = append(entries, entry)
+		if entry.ID == "" && entry.Op == "" {
+			entry.ID = legacyJournalID(line)
+		}
+		records = append(records, entry)
 	}
-	return entries, nil
+	return foldJournal(records), malformed, nil
 }
 
 func (sm *StateManager) LoadHistoryArchive() ([]HistoryEntry, error) {
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
@@ -237,51 +238,68 @@ var Stratagems = map[string]StratagemDef{
 }
 
 func StartStratagem(s *State, name string, force bool) (string, error) {
-	def, ok := Stratagems[name]
-	if !ok {
-		available := make([]string, 0, len(Stratagems))
-		for k := range Stratagems {
-			available = append(available, k)
-		}
-		return "", fmt.Errorf("unknown stratagem %q. Available: %s", name, strings.Join(available, ", "))
+	def, err := lookupStratagem(name)
+	if err != nil {
+		return "", err
 	}
 
 	if s.Stratagem != nil {
 		if !force {
-			return "", fmt.Errorf("%s is active (step %d/%d).\n  Use 'metacog stratagem abort' to abandon it, or\n  Use 'metacog stratagem start %s --force' to replace it",
-				Stratagems[s.Stratagem.Name].Name, s.Stratagem.Step+1, len(Stratagems[s.Stratagem.Name].Steps), name)
+			active := fmt.Sprintf("%q is active", s.Stratagem.Name)
+			if current, err := activeStratagemDef(s); err == nil {
+				active = fmt.Sprintf("%s is active (step %d/%d)", current.Name, s.Stratagem.Step+1, len(current.Steps))
+			}
+			return "", fmt.Errorf("%s.\n  Use 'metacog stratagem abort' to abandon it, or\n  Use 'metacog stratagem start %s --force' to replace it", active, name)
 		}
 		// Record abandoned stratagem
 		s.AddHistory(HistoryEntry{
 			Action: "stratagem",
 			Status: "abandoned",
 			StepAt: s.Stratagem.Step,
-			Params: map[string]string{"name": s.Stratagem.Name},
+			Params: s.Stratagem.historyParams(),
 		})
 		s.Stratagem = nil
 	}
+	CloseConstraintsOn(s, BoundaryStratagem)
 
 	s.Stratagem = &ActiveStratagem{
 		Name:           name,
 		Step:           0,
 		StepsCompleted: []string{},
 		StartedAt:      time.Now().UTC().Format(time.RFC3339),
+		RunID:          uuid.New().String()[:8],
 	}
 
+	params := s.Stratagem.historyParams()
+	params["event"] = "started"
 	s.AddHistory(HistoryEntry{
 		Action: "stratagem",
-		Params: map[string]string{"name": name, "event": "started"},
+		Params: params,
 	})
 
 	return formatStepInstructions(def, 0), nil
 }
 
+// activeStratagemDef looks up the active stratagem's definition. A promoted
+// stratagem can be active while stratagems.json is missing or no longer has
+// it, or has fewer steps than the run has reached.
+func activeStratagemDef(s *State) (StratagemDef, error) {
+	def, ok := Stratagems[s.Stratagem.Name]
+	if !ok || s.Stratagem.Step >= len(def.Steps) {
+		return StratagemDef{}, fmt.Errorf("active stratagem %q is not defined at step %d; check stratagems.json.\n  Restore its definition, or run 'metacog stratagem abort'", s.Stratagem.Name, s.Stratagem.Step+1)
+	}
+	return def, nil
+}
+
 func AdvanceStratagem(s *State) (string, error) {
 	if s.Stratagem == nil {
 		return "", fmt.Errorf("no active stratagem. Start one with 'metacog stratagem start <name>'")
 	}
 
-	def := Stratagems[s.Stratagem.Name]
+	def, err := activeStratagemDef(s)
+	if err != nil {
+		return "", err
+	}
 	currentStep := def.Steps[s.Stratagem.Step]
 
 	// THINK and ACTION steps advance freely
@@ -307,11 +325,14 @@ func AdvanceStratagem(s *State) (string, error) {
 
 	// Check if stratagem is complete
 	if s.Stratagem.Step >= len(def.Steps) {
+		params := s.Stratagem.historyParams()
+		params["event"] = "completed"
 		s.AddHistory(HistoryEntry{
 			Action: "stratagem",
-			Params: map[string]string{"name": s.Stratagem.Name, "event": "completed"},
+			Params: params,
 		})
 		s.Stratagem = nil
+		CloseConstraintsOn(s, BoundaryStratagem)
 		return fmt.Sprintf("%s complete. Ground: name what shifted, what you're keeping, how it integrates.", def.Name), nil
 	}
 
@@ -326,9 +347,10 @@ func AbortStratagem(s *State) error {
 		Action: "stratagem",
 		Status: "aborted",
 		StepAt: s.Stratagem.Step,
-		Params: map[string]string{"name": s.Stratagem.Name},
+		Params: s.Stratagem.historyParams(),
 	})
 	s.Stratagem = nil
+	CloseConstraintsOn(s, BoundaryStratagem)
 	return nil
 }
 
@@ -336,7 +358,10 @@ func StratagemStatus(s *State) string {
 	if s.Stratagem == nil {
 		return "No active stratagem."
 	}
-	def := Stratagems[s.Stratagem.Name]
+	def, err := activeStratagemDef(s)
+	if err != nil {
+		return err.Error() + "\n"
+	}
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("%s — step %d/%d\n", def.Name, s.Stratagem.Step+1, len(def.Steps)))
 	b.WriteString(fmt.Sprintf("Started: %s\n\n", s.Stratagem.StartedAt))
@@ -377,12 +402,12 @@ func ValidatePrimitiveForStratagem(s *State, primitive string) {
 	if s.Stratagem == nil {
 		return
 	}
-	def := Stratagems[s.Stratagem.Name]
-	if s.Stratagem.Step < len(def.Steps) {
-		currentStep := def.Steps[s.Stratagem.Step]
-		if string(currentStep.Kind) == primitive {
-			s.Stratagem.StepsCompleted = append(s.Stratagem.StepsCompleted, primitive)
-		}
+	def, err := activeStratagemDef(s)
+	if err != nil {
+		return
+	}
+	if string(def.Steps[s.Stratagem.Step].Kind) == primitive {
+		s.Stratagem.StepsCompleted = append(s.Stratagem.StepsCompleted, primitive)
 	}
 }
 
@@ -395,11 +420,26 @@ var stratagemCmd = &cobra.Command{
 	Short: "Manage transformation stratagems",
 }
 
+// completeStratagemNames completes a stratagem name argument, promoted ones
+// included; the root command registers them before completion runs.
+func completeStratagemNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, name := range allStratagemNames() {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
 var stratagemStartCmd = &cobra.Command{
-	Use:       "start [name]",
-	Short:     "Start a stratagem",
-	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"pivot", "mirror", "stack", "anchor", "reset", "invocation", "veil", "scrying", "sacrifice", "fool", "inversion", "gift", "zen", "manifold", "chorus", "trinity", "antinomy", "envoy", "counterpoint", "envoy-extreme"},
+	Use:               "start [name]",
+	Short:             "Start a stratagem",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeStratagemNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sm := DefaultStateManager()
 		var output string
@@ -425,7 +465,15 @@ var stratagemNextCmd = &cobra.Command{
 		err := sm.SaveWithLock(func(s *State) error {
 			var err error
 			output, err = AdvanceStratagem(s)
-			return err
+			if err != nil {
+				return err
+			}
+			merged, err := mergeArchivedHistory(sm, s)
+			if err != nil {
+				return err
+			}
+			output += CommitmentReminder(s, merged.History)
+			return nil
 		})
 		if err != nil {
 			return err
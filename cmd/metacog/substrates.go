@@ -0,0 +1,279 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed substrates/*.json
+var substratesFS embed.FS
+
+// Inspiration kinds: what 'inspire' draws.
+const (
+	KindIdentity  = "identity"
+	KindSubstrate = "substrate"
+	KindFull      = "full"
+)
+
+type SubstratePool struct {
+	Name       string
+	Substrates []Substrate
+	Source     string
+}
+
+func LoadSubstratePools() (map[string]SubstratePool, error) {
+	pools := make(map[string]SubstratePool)
+
+	entries, err := substratesFS.ReadDir("substrates")
+	if err != nil {
+		return nil, fmt.Errorf("reading substrates directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := substratesFS.ReadFile("substrates/" + entry.Name())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read %s: %v\n", entry.Name(), err)
+			continue
+		}
+
+		var substrates []Substrate
+		if err := json.Unmarshal(data, &substrates); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not parse %s: %v\n", entry.Name(), err)
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		pools[name] = SubstratePool{
+			Name:       name,
+			Substrates: substrates,
+			Source:     PoolSourceEmbedded,
+		}
+	}
+
+	return pools, nil
+}
+
+// User substrate pools live as $METACOG_HOME/substrates/NAME.json and merge
+// with embedded pools the same way user stance pools do.
+func userSubstratesDir(metacogDir string) string {
+	return filepath.Join(metacogDir, "substrates")
+}
+
+func LoadSubstratePoolsWithUser(metacogDir string) (map[string]SubstratePool, error) {
+	pools, err := LoadSubstratePools()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range listPoolFiles(userSubstratesDir(metacogDir)) {
+		userSubs, err := readPoolFile[Substrate](filepath.Join(userSubstratesDir(metacogDir), name+".json"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+
+		pool, embedded := pools[name]
+		if !embedded {
+			pool = SubstratePool{Name: name, Source: PoolSourceUser}
+		} else {
+			pool.Source = PoolSourceExtended
+		}
+		seen := map[Substrate]bool{}
+		for _, sub := range pool.Substrates {
+			seen[sub] = true
+		}
+		for _, sub := range userSubs {
+			if seen[sub] {
+				continue
+			}
+			seen[sub] = true
+			pool.Substrates = append(pool.Substrates, sub)
+		}
+		pools[name] = pool
+	}
+
+	return pools, nil
+}
+
+func ListSubstratePoolNames(pools map[string]SubstratePool) []string {
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DrawSubstrate picks a pool, then a substrate, from r, visiting pools in
+// sorted order so a seed reproduces the draw.
+func DrawSubstrate(r *rand.Rand, pools map[string]SubstratePool, poolName string) (*Substrate, string, error) {
+	if poolName != "" {
+		pool, ok := pools[poolName]
+		if !ok {
+			return nil, "", fmt.Errorf("unknown substrate pool %q. Use 'inspire --kind substrate --list' to see available pools", poolName)
+		}
+		if len(pool.Substrates) == 0 {
+			return nil, "", fmt.Errorf("substrate pool %q is empty", poolName)
+		}
+		sub := pool.Substrates[r.Intn(len(pool.Substrates))]
+		return &sub, poolName, nil
+	}
+
+	var nonEmpty []string
+	for _, name := range ListSubstratePoolNames(pools) {
+		if len(pools[name].Substrates) > 0 {
+			nonEmpty = append(nonEmpty, name)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return nil, "", fmt.Errorf("no substrate pools loaded")
+	}
+	chosen := nonEmpty[r.Intn(len(nonEmpty))]
+	pool := pools[chosen]
+	sub := pool.Substrates[r.Intn(len(pool.Substrates))]
+	return &sub, chosen, nil
+}
+
+// SaveSubstrate appends the current top substrate to a user substrate pool.
+// The personal pool is created on first save; any other must already exist
+// ('metacog inspire pool create --kind substrate NAME').
+func SaveSubstrate(metacogDir, pool string, s *State) (*Substrate, bool, error) {
+	substrates := s.Substrates()
+	if len(substrates) == 0 {
+		return nil, false, fmt.Errorf("no substrate set. Use 'metacog drugs' first")
+	}
+	if err := validatePoolName(pool); err != nil {
+		return nil, false, err
+	}
+	sub := substrates[len(substrates)-1]
+	dir := userSubstratesDir(metacogDir)
+	path := filepath.Join(dir, pool+".json")
+
+	saved := false
+	err := withPoolLock(metacogDir, func() error {
+		if _, err := os.Stat(path); pool != PersonalPool && err != nil {
+			return fmt.Errorf("no user substrate pool %q. Create it with 'metacog inspire pool create --kind substrate %s'", pool, pool)
+		}
+		existing, err := readPoolFile[Substrate](path)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			if e == sub {
+				return nil
+			}
+		}
+		if err := writePoolFile(dir, pool, append(existing, sub)); err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	return &sub, saved, err
+}
+
+func recordSubstrateInspire(s *State, pool string, sub *Substrate, seed int64, extra map[string]string) {
+	params := map[string]string{
+		"kind":      KindSubstrate,
+		"pool":      pool,
+		"substance": sub.Substance,
+		"seed":      strconv.FormatInt(seed, 10),
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "inspire",
+		Params: params,
+	})
+}
+
+func formatSubstrateDraw(pool string, sub *Substrate) string {
+	return fmt.Sprintf("[%s]\nSubstance: %s\nMethod: %s\nQualia: %s", pool, sub.Substance, sub.Method, sub.Qualia)
+}
+
+// applyInspiredSubstrate takes a drawn substrate verbatim, recording its pool.
+func applyInspiredSubstrate(s *State, pool string, sub *Substrate) string {
+	applyDrugs(s, sub.Substance, sub.Method, sub.Qualia)
+	s.History[len(s.History)-1].Params["pool"] = pool
+	ValidatePrimitiveForStratagem(s, "drugs")
+	return formatDrugs(sub.Substance, sub.Method, sub.Qualia)
+}
+
+// runSubstrateInspire handles 'inspire --kind substrate': list, save, or draw
+// (and optionally apply) a substrate.
+func runSubstrateInspire(sm *StateManager, seed int64, explicit bool) (string, error) {
+	if inspireSave {
+		s, err := sm.Load()
+		if err != nil {
+			return "", err
+		}
+		target := inspirePoolName
+		if target == "" {
+			target = PersonalPool
+		}
+		sub, saved, err := SaveSubstrate(sm.dir, target, s)
+		if err != nil {
+			return "", err
+		}
+		if saved {
+			return fmt.Sprintf("Saved current substrate to substrate pool %s: %s", target, sub.Substance), nil
+		}
+		return fmt.Sprintf("Already saved in substrate pool %s: %s", target, sub.Substance), nil
+	}
+
+	pools, err := LoadSubstratePoolsWithUser(sm.dir)
+	if err != nil {
+		return "", err
+	}
+	if inspireList {
+		names := ListSubstratePoolNames(pools)
+		lines := make([]string, len(names))
+		for i, name := range names {
+			lines[i] = name
+			if src := pools[name].Source; src != PoolSourceEmbedded {
+				lines[i] = fmt.Sprintf("%s (%s)", name, src)
+			}
+		}
+		return fmt.Sprintf("%d substrate pools:\n%s", len(names), strings.Join(lines, "\n")), nil
+	}
+
+	sub, pool, err := DrawSubstrate(rand.New(rand.NewSource(seed)), pools, inspirePoolName)
+	if err != nil {
+		return "", err
+	}
+	var applied string
+	err = sm.SaveWithLock(func(s *State) error {
+		recordSubstrateInspire(s, pool, sub, seed, nil)
+		if inspireApply {
+			applied = applyInspiredSubstrate(s, pool, sub)
+		}
+		return nil
+	})
+	if err != nil {
+		if inspireApply {
+			return "", fmt.Errorf("could not apply drawn substrate: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: could not save state: %v\n", err)
+	}
+
+	output := formatSubstrateDraw(pool, sub)
+	if explicit {
+		output += fmt.Sprintf("\nSeed: %d", seed)
+	}
+	if applied != "" {
+		output += "\n\n" + applied
+	}
+	return output, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSubstratePools(t *testing.T) {
+	pools, err := LoadSubstratePools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) == 0 {
+		t.Fatal("expected embedded substrate pools")
+	}
+	for name, pool := range pools {
+		seen := map[Substrate]bool{}
+		for i, sub := range pool.Substrates {
+			if strings.TrimSpace(sub.Substance) == "" || strings.TrimSpace(sub.Method) == "" || strings.TrimSpace(sub.Qualia) == "" {
+				t.Errorf("%s #%d has an empty field: %+v", name, i+1, sub)
+			}
+			if seen[sub] {
+				t.Errorf("%s #%d duplicates an earlier substrate: %+v", name, i+1, sub)
+			}
+			seen[sub] = true
+		}
+	}
+}
+
+func TestDrawSubstrateDeterministic(t *testing.T) {
+	pools, _ := LoadSubstratePools()
+	a, pa, err := DrawSubstrate(rand.New(rand.NewSource(9)), pools, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, pb, _ := DrawSubstrate(rand.New(rand.NewSource(9)), pools, "")
+	if *a != *b || pa != pb {
+		t.Errorf("same seed drew different substrates: %v/%s vs %v/%s", a, pa, b, pb)
+	}
+	if _, pool, _ := DrawSubstrate(rand.New(rand.NewSource(1)), pools, "stimulants"); pool != "stimulants" {
+		t.Errorf("expected named pool, got %s", pool)
+	}
+	if _, _, err := DrawSubstrate(rand.New(rand.NewSource(1)), pools, "nope"); err == nil {
+		t.Error("expected error for unknown pool")
+	}
+}
+
+func TestLoadSubstratePoolsWithUser(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(userSubstratesDir(dir), 0755)
+	os.WriteFile(filepath.Join(userSubstratesDir(dir), "stimulants.json"), []byte(`[
+		{"substance": "caffeine", "method": "adenosine antagonism", "qualia": "sharp edges, impatient clarity"},
+		{"substance": "guarana", "method": "slow release", "qualia": "long plateau"}
+	]`), 0644)
+	pools, err := LoadSubstratePoolsWithUser(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedded, _ := LoadSubstratePools()
+	got := pools["stimulants"]
+	if got.Source != PoolSourceExtended || len(got.Substrates) != len(embedded["stimulants"].Substrates)+1 {
+		t.Errorf("expected user pool to extend embedded one without duplicates, got %d (%s)", len(got.Substrates), got.Source)
+	}
+}
+
+func TestSaveSubstrate(t *testing.T) {
+	dir := t.TempDir()
+	s := NewState()
+	if _, _, err := SaveSubstrate(dir, PersonalPool, s); err == nil {
+		t.Error("expected error with no substrate")
+	}
+	applyDrugs(s, "static", "interference", "grainy")
+	sub, saved, err := SaveSubstrate(dir, PersonalPool, s)
+	if err != nil || !saved || sub.Substance != "static" {
+		t.Fatalf("expected save, got %v %v %v", sub, saved, err)
+	}
+	if _, saved, _ := SaveSubstrate(dir, PersonalPool, s); saved {
+		t.Error("expected duplicate save to be skipped")
+	}
+	if _, _, err := SaveSubstrate(dir, "team", s); err == nil {
+		t.Error("expected error saving to a missing non-personal pool")
+	}
+	pools, _ := LoadSubstratePoolsWithUser(dir)
+	if p := pools[PersonalPool]; len(p.Substrates) != 1 || p.Source != PoolSourceUser {
+		t.Errorf("expected personal substrate pool with one entry, got %+v", p)
+	}
+}
+
+func TestSubstratePoolRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := CreateSubstratePool(dir, "team"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := CreateSubstratePool(dir, "team"); err == nil {
+		t.Error("expected error creating an existing pool")
+	}
+	if ListUserPools(dir) != nil {
+		t.Error("a substrate pool should not appear among stance pools")
+	}
+
+	s := NewState()
+	applyDrugs(s, "static", "interference", "grainy")
+	if _, saved, err := SaveSubstrate(dir, "team", s); err != nil || !saved {
+		t.Fatalf("save into created pool: %v", err)
+	}
+	if err := RenameSubstratePool(dir, "team", "crew"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	pools, _ := LoadSubstratePoolsWithUser(dir)
+	if p := pools["crew"]; len(p.Substrates) != 1 || p.Substrates[0].Substance != "static" {
+		t.Errorf("expected the saved substrate in crew, got %+v", p)
+	}
+	if _, ok := pools["team"]; ok {
+		t.Error("team should be gone after the rename")
+	}
+
+	if err := DeleteSubstratePool(dir, "../state"); err == nil {
+		t.Error("expected traversal to be refused")
+	}
+	if err := DeleteSubstratePool(dir, "crew"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := DeleteSubstratePool(dir, "crew"); err == nil || !strings.Contains(err.Error(), "no user substrate pool") {
+		t.Errorf("expected missing-pool error, got %v", err)
+	}
+}
+
+func TestApplyInspiredSubstrate(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "stack", false)
+	applyInspiredSubstrate(s, "signals", &Substrate{Substance: "latency", Method: "delay", Qualia: "late"})
+	if s.Substrate == nil || s.Substrate.Substance != "latency" {
+		t.Errorf("expected substrate applied, got %+v", s.Substrate)
+	}
+	if last := s.History[len(s.History)-1]; last.Action != "drugs" || last.Params["pool"] != "signals" {
+		t.Errorf("expected drugs entry tagged with pool, got %+v", last)
+	}
+}
+
+func TestRecordSubstrateInspire(t *testing.T) {
+	s := NewState()
+	recordSubstrateInspire(s, "signals", &Substrate{Substance: "latency"}, 5, nil)
+	h := s.History[0]
+	if h.Params["kind"] != KindSubstrate || h.Params["substance"] != "latency" || h.Params["seed"] != "5" || h.Params["who"] != "" {
+		t.Errorf("unexpected entry: %+v", h)
+	}
+}
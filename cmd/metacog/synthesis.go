@@ -14,17 +14,18 @@ type Lens struct {
 }
 
 var (
-	synProblem  string
-	synAName    string
-	synAVerdict string
-	synABlind   string
-	synBName    string
-	synBVerdict string
-	synBBlind   string
-	synCName    string
-	synCVerdict string
-	synCBlind   string
-	synTension  string
+	synProblem     string
+	synAName       string
+	synAVerdict    string
+	synABlind      string
+	synBName       string
+	synBVerdict    string
+	synBBlind      string
+	synCName       string
+	synCVerdict    string
+	synCBlind      string
+	synTension     string
+	synFromThreads bool
 )
 
 var synthesisCmd = &cobra.Command{
@@ -34,11 +35,25 @@ var synthesisCmd = &cobra.Command{
 		a := Lens{Name: synAName, Verdict: synAVerdict, Blindspot: synABlind}
 		b := Lens{Name: synBName, Verdict: synBVerdict, Blindspot: synBBlind}
 		c := Lens{Name: synCName, Verdict: synCVerdict, Blindspot: synCBlind}
+
+		sm := DefaultStateManager()
+		if synFromThreads {
+			s, err := sm.Load()
+			if err != nil {
+				return err
+			}
+			note, err := lensesFromThreads(s, []*Lens{&a, &b, &c})
+			if err != nil {
+				return err
+			}
+			if note != "" {
+				fmt.Fprintln(cmd.ErrOrStderr(), note)
+			}
+		}
 		if err := validateSynthesis(synProblem, a, b, c, synTension); err != nil {
 			return err
 		}
 
-		sm := DefaultStateManager()
 		output := formatSynthesis(synProblem, a, b, c, synTension)
 
 		err := sm.SaveWithLock(func(s *State) error {
@@ -67,9 +82,40 @@ func init() {
 	synthesisCmd.Flags().StringVar(&synCVerdict, "lens-c-verdict", "", "Third lens: verdict")
 	synthesisCmd.Flags().StringVar(&synCBlind, "lens-c-blindspot", "", "Third lens: structural blindspot")
 	synthesisCmd.Flags().StringVar(&synTension, "suppressed-tension", "", "The irreducible friction between the three blindspots")
+	synthesisCmd.Flags().BoolVar(&synFromThreads, "from-threads", false, "Fill lens names, verdicts and blindspots from surviving fork threads; explicit lens flags take precedence")
 	rootCmd.AddCommand(synthesisCmd)
 }
 
+// lensesFromThreads fills empty lens fields from surviving fork threads, in fork order.
+// All threads must have reported back or been sacrificed first.
+func lensesFromThreads(s *State, lenses []*Lens) (string, error) {
+	if len(s.Threads) == 0 {
+		return "", fmt.Errorf("--from-threads: no tracked threads. Open some with 'metacog fork'")
+	}
+	if open := OpenThreadCount(s); open > 0 {
+		return "", fmt.Errorf("--from-threads: %d threads still open. Report or sacrifice them first with 'metacog thread report|sacrifice'", open)
+	}
+	survivors := SurvivingThreads(s)
+	note := ""
+	if len(survivors) > len(lenses) {
+		note = fmt.Sprintf("Note: %d threads survived; using the first %d as lenses.", len(survivors), len(lenses))
+		survivors = survivors[:len(lenses)]
+	}
+	for i, t := range survivors {
+		l := lenses[i]
+		if l.Name == "" {
+			l.Name = t.Name
+		}
+		if l.Verdict == "" {
+			l.Verdict = t.Finding
+		}
+		if l.Blindspot == "" {
+			l.Blindspot = t.Blindspot
+		}
+	}
+	return note, nil
+}
+
 func validateSynthesis(problem string, a, b, c Lens, tension string) error {
 	if problem == "" || tension == "" {
 		return fmt.Errorf("--problem and --suppressed-tension are required")
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kinds of practice an outcome can evaluate.
+const (
+	TargetStratagem = "stratagem"
+	TargetFreestyle = "freestyle"
+)
+
+// Named --target values.
+const (
+	TargetLastStratagem = "last-stratagem"
+	TargetLastFreestyle = "last-freestyle"
+)
+
+// OutcomeTarget is a unit of practice an outcome can be recorded against: a
+// finished stratagem run, or a freestyle burst (consecutive primitives outside
+// any run, up to the outcome that evaluates them). Indices are into the
+// history the targets were built from.
+type OutcomeTarget struct {
+	Kind string
+	// Name is the stratagem name, or "freestyle"
+	Name string
+	Run  string
+	// Status is how a run ended: completed, aborted or abandoned. Empty for freestyle.
+	Status string
+	// StepAt is the 0-based step an aborted or abandoned run stopped at
+	StepAt     int
+	Start, End int
+	Primitives []int
+	// Outcome is the index of the outcome entry evaluating this target, or -1
+	Outcome int
+}
+
+// Handle is what to pass to 'outcome --target': the run ID when the run has
+// one, otherwise the 1-based history index of the target's last entry.
+func (t OutcomeTarget) Handle() string {
+	if t.Run != "" {
+		return t.Run
+	}
+	return strconv.Itoa(t.End + 1)
+}
+
+func (t OutcomeTarget) String() string {
+	if t.Kind == TargetFreestyle {
+		return fmt.Sprintf("freestyle burst #%d-#%d", t.Start+1, t.End+1)
+	}
+	desc := t.Name
+	if t.Run != "" {
+		desc += " run " + t.Run
+	}
+	if t.Status != "completed" {
+		return fmt.Sprintf("%s (%s at step %d)", desc, t.Status, t.StepAt+1)
+	}
+	return fmt.Sprintf("%s (%s)", desc, t.Status)
+}
+
+// OutcomeTargets walks history and pairs each outcome with the target it
+// evaluates. Outcomes recorded with a "target" param name it by history index;
+// older outcomes are paired the way RecordOutcome chose when they were recorded.
+func OutcomeTargets(history []HistoryEntry) []OutcomeTarget {
+	var targets []OutcomeTarget
+	var run *OutcomeTarget
+	burst := -1
+	for i, h := range history {
+		switch {
+		case h.Action == "stratagem" && h.Params["event"] == "started":
+			burst = -1
+			run = &OutcomeTarget{Kind: TargetStratagem, Name: h.Params["name"], Run: h.Params["run"], Start: i, Outcome: -1}
+		case h.Action == "stratagem" && (h.Params["event"] == "completed" || h.Status == "aborted" || h.Status == "abandoned"):
+			t := OutcomeTarget{Kind: TargetStratagem, Name: h.Params["name"], Run: h.Params["run"], Start: i, Outcome: -1}
+			if run != nil {
+				t = *run
+			}
+			t.End = i
+			t.Status = h.Status
+			if h.Params["event"] == "completed" {
+				t.Status = "completed"
+			}
+			t.StepAt = h.StepAt
+			targets = append(targets, t)
+			run = nil
+		case primitiveActions[h.Action]:
+			if run != nil {
+				run.Primitives = append(run.Primitives, i)
+				continue
+			}
+			if burst < 0 {
+				targets = append(targets, OutcomeTarget{Kind: TargetFreestyle, Name: TargetFreestyle, Start: i, Outcome: -1})
+				burst = len(targets) - 1
+			}
+			targets[burst].End = i
+			targets[burst].Primitives = append(targets[burst].Primitives, i)
+		case h.Action == "outcome":
+			n := pairOutcome(targets, h)
+			if n >= 0 {
+				targets[n].Outcome = i
+			}
+			// An explicit outcome closes only its own burst; older outcomes closed
+			// whatever freestyle came before them.
+			if n == burst || h.Params["target"] == "" {
+				burst = -1
+			}
+		}
+	}
+	return targets
+}
+
+func pairOutcome(targets []OutcomeTarget, h HistoryEntry) int {
+	if ref := h.Params["target"]; ref != "" {
+		idx, err := strconv.Atoi(ref)
+		if err != nil {
+			return -1
+		}
+		for n, t := range targets {
+			if t.End == idx-1 && t.Outcome < 0 {
+				return n
+			}
+		}
+		return -1
+	}
+	kind := TargetStratagem
+	if h.Params["stratagem"] == TargetFreestyle {
+		kind = TargetFreestyle
+	}
+	for n := len(targets) - 1; n >= 0; n-- {
+		t := targets[n]
+		if t.Kind != kind || (kind == TargetStratagem && t.Status != "completed") {
+			continue
+		}
+		if t.Outcome < 0 {
+			return n
+		}
+		return -1
+	}
+	return -1
+}
+
+func lastTarget(targets []OutcomeTarget, match func(OutcomeTarget) bool) *OutcomeTarget {
+	for n := len(targets) - 1; n >= 0; n-- {
+		if match(targets[n]) {
+			return &targets[n]
+		}
+	}
+	return nil
+}
+
+func isCompletedRun(t OutcomeTarget) bool {
+	return t.Kind == TargetStratagem && t.Status == "completed"
+}
+
+func isRun(t OutcomeTarget) bool {
+	return t.Kind == TargetStratagem
+}
+
+func isFreestyle(t OutcomeTarget) bool {
+	return t.Kind == TargetFreestyle
+}
+
+// defaultOutcomeTarget is the heuristic 'outcome' uses without --target: the
+// latest completed run if it has no outcome yet, else the latest freestyle burst.
+func defaultOutcomeTarget(targets []OutcomeTarget) (*OutcomeTarget, error) {
+	run := lastTarget(targets, isCompletedRun)
+	if run != nil && run.Outcome < 0 {
+		return run, nil
+	}
+	if burst := lastTarget(targets, isFreestyle); burst != nil && burst.Outcome < 0 {
+		return burst, nil
+	}
+	if run != nil {
+		return nil, fmt.Errorf("outcome already recorded for this stratagem. Use --amend to update, or --target to evaluate something else")
+	}
+	return nil, fmt.Errorf("no completed stratagem or freestyle primitives found in history")
+}
+
+// ResolveOutcomeTarget finds the target named by ref: last-stratagem (the
+// latest run, however it ended), last-freestyle, a run ID, or a 1-based history
+// index anywhere in a run or burst. Run IDs are matched first since some are
+// all digits; "#N" always means an index. Aborted and abandoned runs are only
+// taken when named; the default stays with completed runs.
+func ResolveOutcomeTarget(targets []OutcomeTarget, ref string) (*OutcomeTarget, error) {
+	var t *OutcomeTarget
+	switch ref {
+	case "":
+		return defaultOutcomeTarget(targets)
+	case TargetLastStratagem:
+		t = lastTarget(targets, isRun)
+		if t == nil {
+			return nil, fmt.Errorf("no stratagem run in history")
+		}
+	case TargetLastFreestyle:
+		t = lastTarget(targets, isFreestyle)
+		if t == nil {
+			return nil, fmt.Errorf("no freestyle primitives in history")
+		}
+	default:
+		t = lastTarget(targets, func(c OutcomeTarget) bool { return c.Run != "" && c.Run == ref })
+		if t == nil {
+			if idx, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+				t = lastTarget(targets, func(c OutcomeTarget) bool { return c.Start <= idx-1 && idx-1 <= c.End })
+			}
+		}
+		if t == nil {
+			return nil, fmt.Errorf("no stratagem run or freestyle burst matches %q. Run 'metacog outcome pending' to see targets", ref)
+		}
+	}
+	return t, nil
+}
+
+// PendingOutcomes lists completed runs and freestyle bursts with no outcome,
+// oldest first. unfinished adds aborted and abandoned runs.
+func PendingOutcomes(targets []OutcomeTarget, unfinished bool) []OutcomeTarget {
+	var pending []OutcomeTarget
+	for _, t := range targets {
+		if t.Outcome < 0 && (isCompletedRun(t) || isFreestyle(t) || (unfinished && isRun(t))) {
+			pending = append(pending, t)
+		}
+	}
+	return pending
+}
+
+func FormatPendingOutcomes(pending []OutcomeTarget, history []HistoryEntry) string {
+	if len(pending) == 0 {
+		return "No runs or freestyle practice awaiting an outcome."
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d awaiting an outcome (record with 'metacog outcome --target HANDLE --result ...'):\n", len(pending)))
+	for _, t := range pending {
+		b.WriteString(fmt.Sprintf("  %-10s %s", t.Handle(), t))
+		if t.Kind == TargetFreestyle {
+			b.WriteString(": " + primitiveSequence(t, history))
+		}
+		b.WriteString(fmt.Sprintf(" [%s]\n", history[t.End].Timestamp))
+	}
+	return b.String()
+}
+
+// primitiveSequence renders a target's primitives as "feel → name → ritual".
+func primitiveSequence(t OutcomeTarget, history []HistoryEntry) string {
+	actions := make([]string, len(t.Primitives))
+	for i, idx := range t.Primitives {
+		actions[i] = history[idx].Action
+	}
+	return strings.Join(actions, " → ")
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func completedRun(s *State, name, run string, identity string) {
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": name, "event": "started", "run": run}})
+	applyBecome(s, identity, "l", "e")
+	s.AddHistory(HistoryEntry{Action: "stratagem", Params: map[string]string{"name": name, "event": "completed", "run": run}})
+}
+
+func TestOutcomeTargetsInterleaved(t *testing.T) {
+	s := NewState()
+	completedRun(s, "pivot", "aaaa1111", "Ada")
+	applyFeel(s, "chest", "warm", "o", "")
+	applyBecome(s, "Eno", "l", "e")
+	completedRun(s, "mirror", "bbbb2222", "Borges")
+
+	targets := OutcomeTargets(s.History)
+	if len(targets) != 3 {
+		t.Fatalf("expected 2 runs and 1 burst, got %+v", targets)
+	}
+	burst := targets[1]
+	if burst.Kind != TargetFreestyle || burst.Start != 3 || burst.End != 4 || burst.Handle() != "5" {
+		t.Errorf("unexpected burst %+v", burst)
+	}
+	if seq := primitiveSequence(burst, s.History); seq != "feel → become" {
+		t.Errorf("expected feel → become, got %q", seq)
+	}
+
+	// Back-fill the older run explicitly, then let the default pick the newer one
+	if _, err := RecordOutcomeFor(s, s.History, "productive", "", "aaaa1111"); err != nil {
+		t.Fatalf("target run: %v", err)
+	}
+	if last := s.History[len(s.History)-1]; last.Params["stratagem"] != "pivot" || last.Params["run"] != "aaaa1111" || last.Params["target"] != "3" {
+		t.Errorf("expected outcome for pivot run at #3, got %v", last.Params)
+	}
+	if _, err := RecordOutcomeFor(s, s.History, "unproductive", "", ""); err != nil {
+		t.Fatalf("default: %v", err)
+	}
+	if last := s.History[len(s.History)-1]; last.Params["stratagem"] != "mirror" {
+		t.Errorf("default should pick the uncovered mirror run, got %v", last.Params)
+	}
+	if _, err := RecordOutcomeFor(s, s.History, "productive", "", "aaaa1111"); err == nil || !strings.Contains(err.Error(), "already recorded") {
+		t.Errorf("expected already-recorded error, got %v", err)
+	}
+
+	pending := PendingOutcomes(OutcomeTargets(s.History), false)
+	if len(pending) != 1 || pending[0].Kind != TargetFreestyle {
+		t.Fatalf("expected only the burst pending, got %+v", pending)
+	}
+	// Any index inside the burst names it
+	if _, err := RecordOutcomeFor(s, s.History, "productive", "", "#4"); err != nil {
+		t.Fatalf("target by index: %v", err)
+	}
+	if pending := PendingOutcomes(OutcomeTargets(s.History), false); len(pending) != 0 {
+		t.Errorf("expected nothing pending, got %+v", pending)
+	}
+
+	records := IdentityOutcomes(s.History)
+	if r := records["ada"]; r.Productive != 1 || r.Total != 1 {
+		t.Errorf("back-filled outcome should credit Ada, got %+v", r)
+	}
+	if r := records["borges"]; r.Productive != 0 || r.Total != 1 {
+		t.Errorf("mirror outcome should credit Borges, got %+v", r)
+	}
+	if r := records["eno"]; r.Productive != 1 || r.Total != 1 {
+		t.Errorf("burst outcome should credit Eno, got %+v", r)
+	}
+}
+
+func TestResolveOutcomeTargetErrors(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "pivot", false)
+	AbortStratagem(s)
+	targets := OutcomeTargets(s.History)
+
+	for _, ref := range []string{TargetLastFreestyle, "nope", "99"} {
+		if _, err := ResolveOutcomeTarget(targets, ref); err == nil {
+			t.Errorf("%s: expected error", ref)
+		}
+	}
+	if _, err := ResolveOutcomeTarget(targets, ""); err == nil {
+		t.Error("the default should not pick an aborted run")
+	}
+}
+
+func TestResolveAllDigitRunID(t *testing.T) {
+	s := NewState()
+	completedRun(s, "pivot", "12345678", "Ada")
+	completedRun(s, "mirror", "2", "Borges")
+	targets := OutcomeTargets(s.History)
+
+	for _, want := range targets {
+		got, err := ResolveOutcomeTarget(targets, want.Handle())
+		if err != nil {
+			t.Fatalf("%s: %v", want.Handle(), err)
+		}
+		if got.Run != want.Run {
+			t.Errorf("%s resolved to run %q", want.Handle(), got.Run)
+		}
+	}
+	// "#2" is the history index inside the pivot run, not the run named "2"
+	if got, err := ResolveOutcomeTarget(targets, "#2"); err != nil || got.Run != "12345678" {
+		t.Errorf("#2 should name the pivot run by index, got %+v, %v", got, err)
+	}
+}
+
+func TestOutcomeOnUnfinishedRun(t *testing.T) {
+	s := NewState()
+	StartStratagem(s, "pivot", false)
+	applyBecome(s, "Ada", "l", "e")
+	s.Stratagem.Step = 1
+	StartStratagem(s, "mirror", true)
+	AbortStratagem(s)
+
+	targets := OutcomeTargets(s.History)
+	if len(PendingOutcomes(targets, false)) != 0 {
+		t.Error("stopped runs should only be pending with unfinished")
+	}
+	pending := PendingOutcomes(targets, true)
+	if len(pending) != 2 || pending[0].Status != "abandoned" || pending[1].Status != "aborted" {
+		t.Fatalf("expected the abandoned and aborted runs, got %+v", pending)
+	}
+	if got := pending[0].String(); !strings.Contains(got, "(abandoned at step 2)") {
+		t.Errorf("expected the stopping step in %q", got)
+	}
+
+	tgt, err := RecordOutcomeFor(s, s.History, "productive", "already working", pending[0].Handle())
+	if err != nil {
+		t.Fatalf("outcome on abandoned run: %v", err)
+	}
+	last := s.History[len(s.History)-1]
+	if tgt.Name != "pivot" || last.Params["status"] != "abandoned" || last.Params["step_at"] != "2" {
+		t.Errorf("expected status and step on the outcome, got %v", last.Params)
+	}
+	if _, err := RecordOutcomeFor(s, s.History, "unproductive", "", TargetLastStratagem); err != nil {
+		t.Errorf("last-stratagem should take the aborted run: %v", err)
+	}
+	if r := IdentityOutcomes(s.History)["ada"]; r.Total != 0 {
+		t.Errorf("an abandoned run should not credit its stances, got %+v", r)
+	}
+}
+
+func TestOutcomeTargetsLegacyPairing(t *testing.T) {
+	history := []HistoryEntry{
+		{Action: "become", Params: map[string]string{"name": "Ada"}},
+		{Action: "outcome", Params: map[string]string{"result": "productive", "stratagem": "freestyle"}},
+		{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "started"}},
+		{Action: "stratagem", Params: map[string]string{"name": "pivot", "event": "completed"}},
+		{Action: "feel", Params: map[string]string{}},
+		{Action: "outcome", Params: map[string]string{"result": "unproductive", "stratagem": "pivot"}},
+	}
+	targets := OutcomeTargets(history)
+	if len(targets) != 3 {
+		t.Fatalf("expected burst, run, burst; got %+v", targets)
+	}
+	if targets[0].Outcome != 1 || targets[1].Outcome != 5 || targets[2].Outcome != -1 {
+		t.Errorf("legacy outcomes should pair as they were recorded, got %d %d %d", targets[0].Outcome, targets[1].Outcome, targets[2].Outcome)
+	}
+	if handle := targets[1].Handle(); handle != "4" {
+		t.Errorf("a run without an ID is named by index, got %s", handle)
+	}
+}
+
+func TestFormatPendingOutcomes(t *testing.T) {
+	s := NewState()
+	completedRun(s, "pivot", "aaaa1111", "Ada")
+	applyFeel(s, "chest", "warm", "o", "")
+	out := FormatPendingOutcomes(PendingOutcomes(OutcomeTargets(s.History), false), s.History)
+	if !strings.Contains(out, "2 awaiting") || !strings.Contains(out, "aaaa1111   pivot run aaaa1111 (completed)") || !strings.Contains(out, "freestyle burst #4-#4: feel") {
+		t.Errorf("unexpected pending listing:\n%s", out)
+	}
+	if FormatPendingOutcomes(nil, nil) != "No runs or freestyle practice awaiting an outcome." {
+		t.Error("expected empty message")
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	ThreadOpen       = "open"
+	ThreadReported   = "reported"
+	ThreadSacrificed = "sacrificed"
+)
+
+type Thread struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Finding   string `json:"finding,omitempty"`
+	Blindspot string `json:"blindspot,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	OpenedAt  string `json:"opened_at"`
+	ClosedAt  string `json:"closed_at,omitempty"`
+	Stratagem string `json:"stratagem,omitempty"`
+}
+
+// OpenThreads replaces the tracked thread set with a fresh fork. Threads from
+// an earlier fork that never reported back are recorded as sacrificed.
+func OpenThreads(s *State, names []string) {
+	for i := range s.Threads {
+		if s.Threads[i].Status == ThreadOpen {
+			s.Threads[i].Status = ThreadSacrificed
+			s.Threads[i].Reason = "superseded by a new fork"
+			recordThreadEvent(s, s.Threads[i])
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	stratagem := ""
+	if s.Stratagem != nil {
+		stratagem = s.Stratagem.Name
+	}
+	s.Threads = make([]Thread, 0, len(names))
+	for _, name := range names {
+		s.Threads = append(s.Threads, Thread{
+			Name:      name,
+			Status:    ThreadOpen,
+			OpenedAt:  now,
+			Stratagem: stratagem,
+		})
+	}
+}
+
+// findThread matches a thread by exact name or by its 1-based position in the fork.
+func findThread(s *State, ref string) (int, error) {
+	if len(s.Threads) == 0 {
+		return -1, fmt.Errorf("no tracked threads. Open some with 'metacog fork'")
+	}
+	for i, t := range s.Threads {
+		if t.Name == ref {
+			return i, nil
+		}
+	}
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(s.Threads) {
+		return n - 1, nil
+	}
+	return -1, fmt.Errorf("no thread %q. Threads: %s", ref, strings.Join(threadNames(s), "; "))
+}
+
+func threadNames(s *State) []string {
+	names := make([]string, len(s.Threads))
+	for i, t := range s.Threads {
+		names[i] = t.Name
+	}
+	return names
+}
+
+func closeThread(s *State, ref, status string, update func(t *Thread)) (*Thread, error) {
+	idx, err := findThread(s, ref)
+	if err != nil {
+		return nil, err
+	}
+	t := &s.Threads[idx]
+	if t.Status != ThreadOpen {
+		return nil, fmt.Errorf("thread %q already %s", t.Name, t.Status)
+	}
+	t.Status = status
+	t.ClosedAt = time.Now().UTC().Format(time.RFC3339)
+	update(t)
+	recordThreadEvent(s, *t)
+	return t, nil
+}
+
+func ReportThread(s *State, ref, finding, blindspot string) (*Thread, error) {
+	if finding == "" {
+		return nil, fmt.Errorf("--finding is required")
+	}
+	return closeThread(s, ref, ThreadReported, func(t *Thread) {
+		t.Finding = finding
+		t.Blindspot = blindspot
+	})
+}
+
+func SacrificeThread(s *State, ref, reason string) (*Thread, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("--reason is required: name the sacrifice condition that fired")
+	}
+	return closeThread(s, ref, ThreadSacrificed, func(t *Thread) {
+		t.Reason = reason
+	})
+}
+
+func recordThreadEvent(s *State, t Thread) {
+	params := map[string]string{"name": t.Name, "event": t.Status}
+	if t.Finding != "" {
+		params["finding"] = t.Finding
+	}
+	if t.Blindspot != "" {
+		params["blindspot"] = t.Blindspot
+	}
+	if t.Reason != "" {
+		params["reason"] = t.Reason
+	}
+	s.AddHistory(HistoryEntry{
+		Action: "thread",
+		Params: params,
+	})
+}
+
+func OpenThreadCount(s *State) int {
+	n := 0
+	for _, t := range s.Threads {
+		if t.Status == ThreadOpen {
+			n++
+		}
+	}
+	return n
+}
+
+// SurvivingThreads returns threads that reported back, in fork order.
+func SurvivingThreads(s *State) []Thread {
+	var out []Thread
+	for _, t := range s.Threads {
+		if t.Status == ThreadReported {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func FormatThreads(s *State) string {
+	if len(s.Threads) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Threads (%d open of %d):\n", OpenThreadCount(s), len(s.Threads)))
+	for i, t := range s.Threads {
+		b.WriteString(fmt.Sprintf("  %d. [%s] %s\n", i+1, t.Status, t.Name))
+		switch t.Status {
+		case ThreadReported:
+			b.WriteString(fmt.Sprintf("     Finding: %s\n", t.Finding))
+		case ThreadSacrificed:
+			b.WriteString(fmt.Sprintf("     Reason: %s\n", t.Reason))
+		}
+	}
+	return b.String()
+}
+
+func formatThreadClosed(s *State, t *Thread) string {
+	var msg string
+	if t.Status == ThreadReported {
+		msg = fmt.Sprintf("Thread %q reported: %s", t.Name, t.Finding)
+	} else {
+		msg = fmt.Sprintf("Thread %q sacrificed: %s", t.Name, t.Reason)
+	}
+	if open := OpenThreadCount(s); open > 0 {
+		return fmt.Sprintf("%s\n%d threads still open. Main thread remains in AWAIT.", msg, open)
+	}
+	return fmt.Sprintf("%s\nAll threads have reported back or been sacrificed. Main thread may proceed; 'metacog synthesis --from-threads' takes the survivors as lenses.", msg)
+}
+
+var (
+	threadFinding   string
+	threadBlindspot string
+	threadReason    string
+)
+
+var threadCmd = &cobra.Command{
+	Use:   "thread",
+	Short: "Report or sacrifice threads opened by fork",
+}
+
+var threadReportCmd = &cobra.Command{
+	Use:   "report [name|number]",
+	Short: "Record a thread's finding and close it as surviving",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		var output string
+		err := sm.SaveWithLock(func(s *State) error {
+			t, err := ReportThread(s, args[0], threadFinding, threadBlindspot)
+			if err != nil {
+				return err
+			}
+			output = formatThreadClosed(s, t)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+var threadSacrificeCmd = &cobra.Command{
+	Use:   "sacrifice [name|number]",
+	Short: "Terminate a thread at its sacrifice point",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		var output string
+		err := sm.SaveWithLock(func(s *State) error {
+			t, err := SacrificeThread(s, args[0], threadReason)
+			if err != nil {
+				return err
+			}
+			output = formatThreadClosed(s, t)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+var threadListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show threads from the most recent fork",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sm := DefaultStateManager()
+		s, err := sm.Load()
+		if err != nil {
+			return err
+		}
+		output := FormatThreads(s)
+		if output == "" {
+			output = "No tracked threads."
+		}
+		fmt.Println(FormatOutput(jsonOutput, output, nil))
+		return nil
+	},
+}
+
+func init() {
+	threadReportCmd.Flags().StringVar(&threadFinding, "finding", "", "What the thread concluded (required)")
+	threadReportCmd.Flags().StringVar(&threadBlindspot, "blindspot", "", "What the thread could not see (used as the lens blindspot by synthesis --from-threads)")
+	threadSacrificeCmd.Flags().StringVar(&threadReason, "reason", "", "Which sacrifice condition fired (required)")
+	threadCmd.AddCommand(threadReportCmd)
+	threadCmd.AddCommand(threadSacrificeCmd)
+	threadCmd.AddCommand(threadListCmd)
+	rootCmd.AddCommand(threadCmd)
+}
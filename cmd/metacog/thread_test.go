@@ -0,0 +1,158 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForkOpensThreads(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"Cassandra", "Pollyanna", "Dispatcher"}, "v", "t")
+
+	if len(s.Threads) != 3 {
+		t.Fatalf("expected 3 threads, got %d", len(s.Threads))
+	}
+	for _, th := range s.Threads {
+		if th.Status != ThreadOpen {
+			t.Errorf("thread %q should start open, got %s", th.Name, th.Status)
+		}
+	}
+	if OpenThreadCount(s) != 3 {
+		t.Errorf("expected 3 open threads, got %d", OpenThreadCount(s))
+	}
+}
+
+func TestReportAndSacrificeThreads(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"Cassandra", "Pollyanna"}, "v", "t")
+
+	if _, err := ReportThread(s, "Cassandra", "the dam breaks in spring", "seasonal repair crews"); err != nil {
+		t.Fatalf("report failed: %v", err)
+	}
+	if _, err := SacrificeThread(s, "2", "required an assumption outside the premises"); err != nil {
+		t.Fatalf("sacrifice by number failed: %v", err)
+	}
+	if OpenThreadCount(s) != 0 {
+		t.Errorf("expected no open threads, got %d", OpenThreadCount(s))
+	}
+
+	var events []string
+	for _, h := range s.History {
+		if h.Action == "thread" {
+			events = append(events, h.Params["event"])
+		}
+	}
+	if strings.Join(events, ",") != "reported,sacrificed" {
+		t.Errorf("expected reported,sacrificed events, got %v", events)
+	}
+
+	survivors := SurvivingThreads(s)
+	if len(survivors) != 1 || survivors[0].Finding != "the dam breaks in spring" {
+		t.Errorf("expected Cassandra as sole survivor, got %+v", survivors)
+	}
+}
+
+func TestThreadCannotCloseTwice(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"a", "b"}, "v", "t")
+	ReportThread(s, "a", "finding", "")
+	if _, err := SacrificeThread(s, "a", "late"); err == nil {
+		t.Error("expected error closing an already-reported thread")
+	}
+}
+
+func TestThreadRequiresFindingAndReason(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"a", "b"}, "v", "t")
+	if _, err := ReportThread(s, "a", "", ""); err == nil {
+		t.Error("expected error without --finding")
+	}
+	if _, err := SacrificeThread(s, "a", ""); err == nil {
+		t.Error("expected error without --reason")
+	}
+}
+
+func TestThreadUnknownName(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"a", "b"}, "v", "t")
+	if _, err := ReportThread(s, "zeta", "f", ""); err == nil {
+		t.Error("expected error for unknown thread")
+	}
+	if _, err := ReportThread(s, "3", "f", ""); err == nil {
+		t.Error("expected error for out-of-range thread number")
+	}
+}
+
+func TestNewForkSupersedesOpenThreads(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"a", "b"}, "v", "t")
+	ReportThread(s, "a", "f", "")
+	applyFork(s, []string{"c", "d"}, "v2", "t2")
+
+	if len(s.Threads) != 2 || s.Threads[0].Name != "c" {
+		t.Fatalf("expected the new fork's threads, got %+v", s.Threads)
+	}
+	superseded := false
+	for _, h := range s.History {
+		if h.Action == "thread" && h.Params["name"] == "b" && h.Params["event"] == ThreadSacrificed {
+			superseded = true
+		}
+	}
+	if !superseded {
+		t.Error("expected open thread b to be recorded as sacrificed")
+	}
+}
+
+func TestFormatStatusShowsOpenThreads(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"Cassandra", "Pollyanna"}, "v", "t")
+	out := FormatStatus(s)
+	if !strings.Contains(out, "Threads (2 open of 2)") || !strings.Contains(out, "[open] Cassandra") {
+		t.Errorf("status should show open threads:\n%s", out)
+	}
+
+	ReportThread(s, "1", "f", "")
+	SacrificeThread(s, "2", "r")
+	if strings.Contains(FormatStatus(s), "Threads (") {
+		t.Error("status should omit threads once none are open")
+	}
+}
+
+func TestLensesFromThreads(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"Cassandra", "Pollyanna", "Dispatcher", "Fourth"}, "v", "t")
+	ReportThread(s, "Cassandra", "worst case", "recovery")
+	SacrificeThread(s, "Pollyanna", "left the premises")
+	ReportThread(s, "Dispatcher", "commit now", "timing")
+	ReportThread(s, "Fourth", "wait", "cost")
+
+	a := Lens{}
+	b := Lens{Name: "explicit"}
+	c := Lens{}
+	note, err := lensesFromThreads(s, []*Lens{&a, &b, &c})
+	if err != nil {
+		t.Fatalf("lensesFromThreads failed: %v", err)
+	}
+	if note != "" {
+		t.Errorf("three survivors fit three lenses, got note %q", note)
+	}
+	if a.Name != "Cassandra" || a.Verdict != "worst case" || a.Blindspot != "recovery" {
+		t.Errorf("lens a: %+v", a)
+	}
+	if b.Name != "explicit" || b.Verdict != "commit now" {
+		t.Errorf("explicit flags should win, lens b: %+v", b)
+	}
+	if c.Name != "Fourth" {
+		t.Errorf("lens c: %+v", c)
+	}
+}
+
+func TestLensesFromThreadsRequiresClosedThreads(t *testing.T) {
+	s := NewState()
+	applyFork(s, []string{"a", "b"}, "v", "t")
+	ReportThread(s, "a", "f", "")
+	var a, b, c Lens
+	if _, err := lensesFromThreads(s, []*Lens{&a, &b, &c}); err == nil {
+		t.Error("expected error while a thread is still open")
+	}
+}